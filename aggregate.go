@@ -0,0 +1,161 @@
+package sqlmy
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// Count returns the count of rows matching where
+func (curd *CURD[Data, Param]) Count(ctx context.Context, where *Param, opts ...curdOpt) (int64, error) {
+	var count sql.NullInt64
+	err := curd.aggregate(ctx, "Count", internal.AggCount, "*", where, &count, opts...)
+	return count.Int64, err
+}
+
+// AggFunc is the aggregate function of Aggregate
+type AggFunc string
+
+// the aggregate functions
+const (
+	AggCount AggFunc = internal.AggCount
+	AggSum   AggFunc = internal.AggSum
+	AggMax   AggFunc = internal.AggMax
+	AggMin   AggFunc = internal.AggMin
+	AggAvg   AggFunc = internal.AggAvg
+)
+
+// Aggregate returns the aggregate fn of column of rows matching where scanned into T, it's the zero T if no row matched.
+// T is the type of the column like time.Time for `MAX(created_at)`, or int64 and string for the exact SUM of BIGINT and DECIMAL
+func Aggregate[T, Data, Param any](ctx context.Context, curd *CURD[Data, Param], fn AggFunc, column string, where *Param, opts ...curdOpt) (T, error) {
+	return aggregateOf[T](ctx, curd, "Aggregate", string(fn), column, where, opts...)
+}
+
+// Sum returns the sum of column of rows matching where as float64, it's 0 if no row matched
+func (curd *CURD[Data, Param]) Sum(ctx context.Context, column string, where *Param, opts ...curdOpt) (float64, error) {
+	return aggregateOf[float64](ctx, curd, "Sum", internal.AggSum, column, where, opts...)
+}
+
+// Max returns the max value of column of rows matching where as float64, it's 0 if no row matched,
+// use Aggregate for the columns not of number
+func (curd *CURD[Data, Param]) Max(ctx context.Context, column string, where *Param, opts ...curdOpt) (float64, error) {
+	return aggregateOf[float64](ctx, curd, "Max", internal.AggMax, column, where, opts...)
+}
+
+// Min returns the min value of column of rows matching where as float64, it's 0 if no row matched,
+// use Aggregate for the columns not of number
+func (curd *CURD[Data, Param]) Min(ctx context.Context, column string, where *Param, opts ...curdOpt) (float64, error) {
+	return aggregateOf[float64](ctx, curd, "Min", internal.AggMin, column, where, opts...)
+}
+
+// Avg returns the average value of column of rows matching where as float64, it's 0 if no row matched
+func (curd *CURD[Data, Param]) Avg(ctx context.Context, column string, where *Param, opts ...curdOpt) (float64, error) {
+	return aggregateOf[float64](ctx, curd, "Avg", internal.AggAvg, column, where, opts...)
+}
+
+// aggregateOf scans the aggregate into *T so that NULL is the zero T
+func aggregateOf[T, Data, Param any](ctx context.Context, curd *CURD[Data, Param], action, fn, column string, where *Param, opts ...curdOpt) (T, error) {
+	var value *T
+	if err := curd.aggregate(ctx, action, fn, column, where, &value, opts...); err != nil || value == nil {
+		var zero T
+		return zero, err
+	}
+	return *value, nil
+}
+
+// aggregate queries the aggregate fn of column and scans the only value into dest,
+// the column must be one of Data
+func (curd *CURD[Data, Param]) aggregate(ctx context.Context, action, fn, column string, where *Param, dest any, opts ...curdOpt) error {
+	begin := time.Now()
+	option := curd.newOption(ctx, opts...)
+
+	err := option.checkNoLock()
+	var query string
+	var args []any
+	if err == nil {
+		query, args, err = option.builder.BuildAggregate(curd.table, fn, column, where)
+	}
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [%sBuild] table[%s] err[%v]", costMs(begin), action, curd.table, err)
+		return err
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "["+action+"Build]", query, args)
+
+	rows, err := curdQuery(ctx, query, args)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [%sQuery] sql[%s] err[%v]", costMs(begin), action, sqlDeal(query), err)
+		return err
+	}
+	if rows != nil {
+		err = scanValue(rows, dest)
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [%sScan] sql[%s] err[%v]", costMs(begin), action, sqlDeal(query), err)
+			return err
+		}
+	}
+
+	logger.Info(ctx, "cost[%d] [%sSucc] table[%s]", costMs(begin), action, curd.table)
+	return nil
+}
+
+// scanValue scans the value of the only row into dest and closes rows
+func scanValue(rows *sql.Rows, dest any) error {
+	defer rows.Close()
+
+	if !rows.Next() {
+		return rows.Err()
+	}
+	if err := rows.Scan(dest); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// QueryGroup queries the rows matching where grouped by the columns, and scans them into Result.
+// The field of Result tagged like `db:"total" agg:"sum(score)"` is selected as `SUM(score) AS total`,
+// the other fields of Result must be the columns of groupBy.
+// The columns of groupBy and aggregates must be the ones of Data.
+func QueryGroup[Result, Data, Param any](ctx context.Context, curd *CURD[Data, Param], groupBy []string, where *Param, opts ...curdOpt) ([]*Result, error) {
+	begin := time.Now()
+	option := curd.newOption(ctx, opts...)
+
+	err := option.checkNoLock()
+	var items []internal.Aggregate
+	if err == nil {
+		items, err = internal.GroupItems(reflect.TypeOf((*Result)(nil)))
+	}
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryGroupBuild] table[%s] err[%v]", costMs(begin), curd.table, err)
+		return nil, err
+	}
+	query, args, err := option.builder.BuildGroupQuery(curd.table, items, groupBy, where)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryGroupBuild] table[%s] err[%v]", costMs(begin), curd.table, err)
+		return nil, err
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[QueryGroupBuild]", query, args)
+
+	rows, err := curdQuery(ctx, query, args)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryGroupQuery] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+		return nil, err
+	}
+
+	var results []*Result
+	if rows != nil {
+		results = []*Result{}
+		err = option.rowsScan(rows, &results)
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [QueryGroupScan] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+			return nil, err
+		}
+	}
+
+	logger.Info(ctx, "cost[%d] [QueryGroupSucc] table[%s] len[%d]", costMs(begin), curd.table, len(results))
+	return results, nil
+}
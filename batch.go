@@ -0,0 +1,92 @@
+package sqlmy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BatchError is the error of the failed batches of InsertBatches, InsertList and UpdateList with WithContinueOnError
+type BatchError struct {
+	// Batches are the indexes of the failed batches
+	Batches []int
+	// Errs are the errors of Batches
+	Errs []error
+}
+
+func (e *BatchError) Error() string {
+	items := make([]string, 0, len(e.Errs))
+	for i, err := range e.Errs {
+		items = append(items, fmt.Sprintf("[%d] %v", e.Batches[i], err))
+	}
+	return fmt.Sprintf("%d batches failed: %s", len(e.Errs), strings.Join(items, "; "))
+}
+
+// Unwrap returns the errors of the failed batches
+func (e *BatchError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is reports whether the error of any failed batch matches target,
+// errors.Is before go 1.20 does not unwrap []error so it is implemented here
+func (e *BatchError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the failed batches that matches target
+func (e *BatchError) As(target any) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *BatchError) add(batch int, err error) {
+	e.Batches = append(e.Batches, batch)
+	e.Errs = append(e.Errs, err)
+}
+
+// err returns nil if no batch failed
+func (e *BatchError) err() error {
+	if len(e.Errs) == 0 {
+		return nil
+	}
+	return e
+}
+
+// WithAtomic runs all batches of InsertList and UpdateList in one transaction by TxExec,
+// the outer transaction is joined if ctx is in TxExec. Any failed batch rolls back all,
+// so WithContinueOnError is ignored
+func WithAtomic() curdOpt {
+	return func(co *curdOption) {
+		co.atomic = true
+	}
+}
+
+// WithContinueOnError makes InsertList and UpdateList go on with the next batch when one fails,
+// the errors of the failed batches are returned by BatchError
+func WithContinueOnError() curdOpt {
+	return func(co *curdOption) {
+		co.continueOnErr = true
+	}
+}
+
+func (co *curdOption) continueOnError() bool {
+	return co.continueOnErr && !co.atomic
+}
+
+// atomically runs do in TxExec if WithAtomic, nothing is executed in dry run so no transaction is needed
+func (co *curdOption) atomically(ctx context.Context, do func(ctx context.Context) error) error {
+	if !co.atomic || GetRecorder(ctx) != nil {
+		return do(ctx)
+	}
+	return TxExec(ctx, do)
+}
@@ -0,0 +1,201 @@
+package sqlmy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var batchStudents = []*StudentParam{
+	{ID: P(int64(1)), Name: P("n1")},
+	{ID: P(int64(2)), Name: P("n2")},
+	{ID: P(int64(3)), Name: P("n3")},
+}
+
+func ExampleWithAtomic() {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+
+	mock.ExpectBegin()
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\),\(\?,\?\)`).
+		WithArgs(1, "n1", 2, "n2").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(3, "n3").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	id, err := StudentCURD.InsertList(ctx, batchStudents, WithInsertBatchSize(2), WithAtomic())
+	fmt.Println(id)
+	fmt.Println(err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: 0
+	// sql: connection is already closed
+}
+
+func TestWithAtomicJoinTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectBegin()
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\),\(\?,\?\)`).
+		WithArgs(1, "n1", 2, "n2").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(3, "n3").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.
+		ExpectExec(`UPDATE students SET status=\? WHERE \(id=\?\)`).
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = TxExec(ctx, func(ctx context.Context) error {
+		if _, err := StudentCURD.InsertList(ctx, batchStudents, WithInsertBatchSize(2), WithAtomic()); err != nil {
+			return err
+		}
+		_, err := StudentCURD.Update(ctx, &StudentParam{ID: P(int64(1))}, &StudentParam{Status: P(1)})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithContinueOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(1, "n1").
+		WillReturnError(sql.ErrConnDone)
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(2, "n2").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(3, "n3").
+		WillReturnError(sql.ErrTxDone)
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := StudentCURD.InsertBatches(ctx, batchStudents, WithInsertBatchSize(1), WithContinueOnError())
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("InsertBatches() err = %v, want BatchError", err)
+	}
+	if len(batchErr.Batches) != 2 || batchErr.Batches[0] != 0 || batchErr.Batches[1] != 2 {
+		t.Errorf("BatchError.Batches = %v, want [0 2]", batchErr.Batches)
+	}
+	if want := "2 batches failed: [0] sql: connection is already closed; [2] sql: transaction has already been committed or rolled back"; err.Error() != want {
+		t.Errorf("BatchError.Error() = %v, want %v", err, want)
+	}
+	if !errors.Is(err, sql.ErrConnDone) || !errors.Is(err, sql.ErrTxDone) || errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("errors.Is(%v) should match the errors of failed batches only", err)
+	}
+	if len(results) != 3 || results[0].Err != sql.ErrConnDone || results[1].Err != nil || results[1].LastInsertedID != 2 || results[2].Err != sql.ErrTxDone {
+		t.Errorf("InsertBatches() results = %+v", results)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+type batchTestErr struct{ code int }
+
+func (e *batchTestErr) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestBatchErrorAs(t *testing.T) {
+	err := error(&BatchError{
+		Batches: []int{1, 3},
+		Errs:    []error{sql.ErrConnDone, fmt.Errorf("insert: %w", &batchTestErr{code: 2})},
+	})
+
+	var target *batchTestErr
+	if !errors.As(err, &target) || target.code != 2 {
+		t.Errorf("errors.As() = %v, want code 2", target)
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		t.Errorf("errors.As() = %v, want no match", pathErr)
+	}
+}
+
+func TestInsertBatchesInsertedIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 rows are inserted by one plain insert, the ids are consecutive from LastInsertId
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\),\(\?,\?\),\(\?,\?\)`).
+		WillReturnResult(sqlmock.NewResult(1, 3))
+	// the replaced row is deleted and inserted, its RowsAffected is 2
+	mock.
+		ExpectExec(`REPLACE INTO students \(id,name\) VALUES \(\?,\?\)`).
+		WithArgs(7, "n7").
+		WillReturnResult(sqlmock.NewResult(7, 2))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := StudentCURD.InsertBatches(ctx, batchStudents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].FirstInsertedID != 1 || results[0].LastInsertedID != 3 {
+		t.Errorf("InsertBatches() results = %+v", results)
+	}
+
+	results, err = StudentCURD.InsertBatches(ctx, []*StudentParam{{ID: P(int64(7)), Name: P("n7")}},
+		WithInsertType(InsertTypeReplaceInsert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].FirstInsertedID != 7 || results[0].LastInsertedID != 7 || results[0].RowsAffected != 2 {
+		t.Errorf("InsertBatches() replace results = %+v", results)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,114 @@
+package sqlmy
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// SQLComment configs the comment like `/* log_id=..., caller=pkg.Func, app=... */`
+// which is prepended to the sql issued by CURD, so the sql in processlist and slow log
+// can be mapped back to the request
+// the comment only contains letters, digits and `_.-:@()[]*`, others will be replaced by `_`,
+// so it can't close the comment or carry placeholders
+// NOTICE: log_id makes the sql text different every request, turn it off if sql text cache matters
+type SQLComment struct {
+	LogID  bool
+	Caller bool
+	Tags   map[string]string
+}
+
+var sqlComment *SQLComment
+
+// SetSQLComment sets the default comment for all CURD, nil means no comment
+func SetSQLComment(comment *SQLComment) {
+	sqlComment = comment
+}
+
+// WithSQLComment overwrites the default comment, nil means no comment
+func WithSQLComment(comment *SQLComment) curdOpt {
+	return func(co *curdOption) {
+		co.comment = comment
+		co.commentSet = true
+	}
+}
+
+func (co *curdOption) sqlComment() *SQLComment {
+	if co.commentSet {
+		return co.comment
+	}
+	return sqlComment
+}
+
+// commentSQL prepends the comment to query
+func commentSQL(ctx context.Context, comment *SQLComment, query string) string {
+	if comment == nil {
+		return query
+	}
+
+	items := make([]string, 0, len(comment.Tags)+2)
+	if comment.LogID {
+		if logID := GetLogID(ctx); logID != "" {
+			items = append(items, "log_id="+commentEscape(logID))
+		}
+	}
+	if comment.Caller {
+		if caller := callerName(); caller != "" {
+			items = append(items, "caller="+commentEscape(caller))
+		}
+	}
+	keys := make([]string, 0, len(comment.Tags))
+	for k := range comment.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		items = append(items, commentEscape(k)+"="+commentEscape(comment.Tags[k]))
+	}
+
+	if len(items) == 0 {
+		return query
+	}
+	return "/* " + strings.Join(items, ", ") + " */ " + query
+}
+
+func commentEscape(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case strings.ContainsRune("_.-:@()[]*", r):
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+var pkgPath = reflect.TypeOf(dbContext{}).PkgPath()
+
+// callerName returns the first function out of this package in call stack like `pkg.Func`
+func callerName() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !inPkg(frame.Function, pkgPath) {
+			return frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// inPkg reports whether the function name like `path/to/pkg.Func` belongs to pkg
+func inPkg(function, pkg string) bool {
+	if !strings.HasPrefix(function, pkg) {
+		return false
+	}
+	rest := function[len(pkg):]
+	return strings.HasPrefix(rest, ".")
+}
@@ -0,0 +1,133 @@
+package sqlmy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCommentSQL(t *testing.T) {
+	ctx := WithLogID(context.Background(), "lid1")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		comment *SQLComment
+		want    string
+	}{
+		{
+			name:    "nil",
+			ctx:     ctx,
+			comment: nil,
+			want:    "SELECT 1",
+		},
+		{
+			name:    "empty",
+			ctx:     ctx,
+			comment: &SQLComment{},
+			want:    "SELECT 1",
+		},
+		{
+			name:    "log id",
+			ctx:     ctx,
+			comment: &SQLComment{LogID: true},
+			want:    "/* log_id=lid1 */ SELECT 1",
+		},
+		{
+			name:    "no log id",
+			ctx:     context.Background(),
+			comment: &SQLComment{LogID: true},
+			want:    "SELECT 1",
+		},
+		{
+			name:    "tags",
+			ctx:     ctx,
+			comment: &SQLComment{LogID: true, Tags: map[string]string{"app": "a1", "env": "prod"}},
+			want:    "/* log_id=lid1, app=a1, env=prod */ SELECT 1",
+		},
+		{
+			name:    "injection",
+			ctx:     WithLogID(context.Background(), "*/ DROP TABLE t; /* ?"),
+			comment: &SQLComment{LogID: true, Tags: map[string]string{"a=b, c": "'$1\""}},
+			want:    "/* log_id=*__DROP_TABLE_t___*__, a_b__c=__1_ */ SELECT 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commentSQL(tt.ctx, tt.comment, "SELECT 1"); got != tt.want {
+				t.Errorf("commentSQL() = `%v`, want `%v`", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommentSQLCaller(t *testing.T) {
+	got := commentSQL(context.Background(), &SQLComment{Caller: true}, "SELECT 1")
+	if want := "/* caller=testing.tRunner */ SELECT 1"; got != want {
+		t.Errorf("commentSQL() = `%v`, want `%v`", got, want)
+	}
+
+	if strings.Count(got, "*/") != 1 {
+		t.Errorf("comment is not closed only once: %s", got)
+	}
+}
+
+func TestInPkg(t *testing.T) {
+	tests := []struct {
+		function string
+		want     bool
+	}{
+		{function: "github.com/liuximu/sqlmy.(*CURD[...]).QueryList", want: true},
+		{function: "github.com/liuximu/sqlmy.commentSQL", want: true},
+		{function: "github.com/liuximu/sqlmy/internal.BuildQuery", want: false},
+		{function: "github.com/liuximu/sqlmyx.Func", want: false},
+		{function: "main.main", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			if got := inPkg(tt.function, "github.com/liuximu/sqlmy"); got != tt.want {
+				t.Errorf("inPkg() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithSQLComment(t *testing.T) {
+	SetSQLComment(&SQLComment{Tags: map[string]string{"app": "default"}})
+	defer SetSQLComment(nil)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectExec(`/* app=default */ DELETE FROM students WHERE (id=?)`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`/* log_id=lid2, app=call */ DELETE FROM students WHERE (id=?)`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM students WHERE (id=?)`).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(WithLogID(context.Background(), "lid2"), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := StudentCURD.Delete(ctx, &StudentParam{ID: P(int64(1))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StudentCURD.Delete(ctx, &StudentParam{ID: P(int64(2))},
+		WithSQLComment(&SQLComment{LogID: true, Tags: map[string]string{"app": "call"}})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StudentCURD.Delete(ctx, &StudentParam{ID: P(int64(3))}, WithSQLComment(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
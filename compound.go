@@ -0,0 +1,158 @@
+package sqlmy
+
+import (
+	"context"
+	"time"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// Compound is the select of Data combined by UNION, UNION ALL and WITH, it is created by CURD.Select
+type Compound[Data any] struct {
+	selects   []compoundSelect
+	unions    []string
+	with      []compoundCTE
+	table     string
+	newOption func(ctx context.Context, opts ...curdOpt) *curdOption
+}
+
+// compoundSelect is a select of Compound, its fields are resolved by the context and options of the query
+type compoundSelect struct {
+	table     string
+	where     any
+	newOption func(ctx context.Context, opts ...curdOpt) *curdOption
+	opts      []curdOpt
+}
+
+type compoundCTE struct {
+	name  string
+	query Compounder
+}
+
+// Compounder is the query of WITH, it's any Compound
+type Compounder interface {
+	compound(ctx context.Context, opts ...curdOpt) (*internal.Compound, error)
+}
+
+// compound resolves the selects with ctx, opts are applied to every select before the opts of Select,
+// the CTEs are resolved with ctx only as their Data differ
+func (c *Compound[Data]) compound(ctx context.Context, opts ...curdOpt) (*internal.Compound, error) {
+	compound := &internal.Compound{Unions: c.unions}
+	for _, cte := range c.with {
+		query, err := cte.query.compound(ctx)
+		if err != nil {
+			return nil, err
+		}
+		compound.With = append(compound.With, internal.CTE{Name: cte.name, Query: query})
+	}
+	for _, sel := range c.selects {
+		all := append(append(make([]curdOpt, 0, len(opts)+len(sel.opts)), opts...), sel.opts...)
+		option := sel.newOption(ctx, all...)
+		if err := option.checkNoLock(); err != nil {
+			return nil, err
+		}
+		fields, err := option.selectFields()
+		if err != nil {
+			return nil, err
+		}
+		compound.Selects = append(compound.Selects, internal.Select{
+			Table:   sel.table,
+			Fields:  fields,
+			Where:   sel.where,
+			Columns: option.columns,
+			Hints:   &option.hints,
+		})
+	}
+	return compound, nil
+}
+
+// Select returns the Compound selecting the columns of Data from curd's table by where,
+// opts like WithSelectFileds set the selected columns of this select, they are applied after the opts of QueryList
+func (curd *CURD[Data, Param]) Select(where *Param, opts ...curdOpt) *Compound[Data] {
+	return &Compound[Data]{
+		selects: []compoundSelect{{
+			table:     curd.table,
+			where:     where,
+			newOption: curd.newOption,
+			opts:      opts,
+		}},
+		table:     curd.table,
+		newOption: curd.newOption,
+	}
+}
+
+// Union returns the Compound combining c and other by `UNION`
+func (c *Compound[Data]) Union(other *Compound[Data]) *Compound[Data] {
+	return c.union(internal.Union, other)
+}
+
+// UnionAll returns the Compound combining c and other by `UNION ALL`
+func (c *Compound[Data]) UnionAll(other *Compound[Data]) *Compound[Data] {
+	return c.union(internal.UnionAll, other)
+}
+
+// union returns a new Compound of the selects of c and other, the CTEs of other are moved to its WITH,
+// c and other are not changed
+func (c *Compound[Data]) union(op string, other *Compound[Data]) *Compound[Data] {
+	u := c.clone()
+	u.with = append(u.with, other.with...)
+	u.unions = append(append(u.unions, op), other.unions...)
+	u.selects = append(u.selects, other.selects...)
+	return u
+}
+
+// With returns a new Compound with the common table expression `name AS (query)`, the CURD of table name selects from it
+func (c *Compound[Data]) With(name string, query Compounder) *Compound[Data] {
+	w := c.clone()
+	w.with = append(w.with, compoundCTE{name: name, query: query})
+	return w
+}
+
+// clone copies c with its own slices, so that appending to them does not change c
+func (c *Compound[Data]) clone() *Compound[Data] {
+	return &Compound[Data]{
+		selects:   append([]compoundSelect(nil), c.selects...),
+		unions:    append([]string(nil), c.unions...),
+		with:      append([]compoundCTE(nil), c.with...),
+		table:     c.table,
+		newOption: c.newOption,
+	}
+}
+
+// QueryList queries the compound and scans the rows into Data, opts are applied to the query and every select of Data
+func (c *Compound[Data]) QueryList(ctx context.Context, opts ...curdOpt) ([]*Data, error) {
+	begin := time.Now()
+	option := c.newOption(ctx, opts...)
+
+	var query string
+	var args []any
+	compound, err := c.compound(ctx, opts...)
+	if err == nil {
+		query, args, err = option.builder.BuildCompound(compound)
+	}
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryCompoundBuild] table[%s] err[%v]", costMs(begin), c.table, err)
+		return nil, err
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[QueryCompoundBuild]", query, args)
+
+	rows, err := curdQuery(ctx, query, args)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryCompoundQuery] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+		return nil, err
+	}
+
+	var datas []*Data
+	if rows != nil {
+		datas = []*Data{}
+		err = option.rowsScan(rows, &datas)
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [QueryCompoundScan] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+			return nil, err
+		}
+	}
+
+	logger.Info(ctx, "cost[%d] [QueryCompoundSucc] table[%s] len[%d]", costMs(begin), c.table, len(datas))
+	return datas, nil
+}
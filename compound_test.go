@@ -0,0 +1,107 @@
+package sqlmy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func ExampleCompound_UnionAll() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectQuery(`WITH recent AS (SELECT id,name,status FROM students WHERE (id>?)) `+
+			`SELECT id,name,status FROM recent WHERE (status=?) UNION ALL SELECT id,name,status FROM recent WHERE (status=?)`).
+		WithArgs(100, 1, 2).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status"}).
+				AddRow(101, "N1", 1).
+				AddRow(102, "N2", 2),
+		)
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	type recentParam struct {
+		ID *int64 `db:"id,>"`
+	}
+	recentCURD := NewCURD[Student, StudentParam]("recent")
+	students, err := recentCURD.Select(&StudentParam{Status: P(1)}).
+		UnionAll(recentCURD.Select(&StudentParam{Status: P(2)})).
+		With("recent", NewCURD[Student, recentParam]("students").Select(&recentParam{ID: P(int64(100))})).
+		QueryList(ctx)
+	fmt.Println(err)
+	for _, student := range students {
+		fmt.Println(student.ID, student.Name, student.Status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: <nil>
+	// 101 N1 1
+	// 102 N2 2
+}
+
+func TestCompoundOptions(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	// the opts of QueryList apply to every select, the opts of Select override them
+	_, err := StudentCURD.Select(&StudentParam{Status: P(1)}, WithSelectFileds("id", "name", "status")).
+		UnionAll(StudentCURD.Select(&StudentParam{Status: P(2)})).
+		QueryList(ctx, WithSelectAll())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT id,name,status FROM students WHERE (status=?) UNION ALL SELECT * FROM students WHERE (status=?)"
+	if got := GetRecorder(ctx).Statements()[0].SQL; got != want {
+		t.Errorf("QueryList() sql = `%v`, want `%v`", got, want)
+	}
+
+	_, err = StudentCURD.Select(nil, WithSelectExprs(As("COUNT(*)", "cnt"))).QueryList(ctx)
+	if !errors.Is(err, ErrColumnNotAllowed) {
+		t.Errorf("QueryList() err = %v, want %v", err, ErrColumnNotAllowed)
+	}
+}
+
+func TestCompoundUnchanged(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	a := StudentCURD.Select(&StudentParam{Status: P(1)})
+	b := StudentCURD.Select(&StudentParam{Status: P(2)})
+	ab := a.UnionAll(b)
+	abc := ab.Union(StudentCURD.Select(&StudentParam{Status: P(3)}))
+	recent := ab.With("recent", StudentCURD.Select(nil))
+	for _, c := range []*Compound[Student]{a, ab, abc, recent} {
+		if _, err := c.QueryList(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"SELECT id,name,status FROM students WHERE (status=?)",
+		"SELECT id,name,status FROM students WHERE (status=?) UNION ALL SELECT id,name,status FROM students WHERE (status=?)",
+		"SELECT id,name,status FROM students WHERE (status=?) UNION ALL SELECT id,name,status FROM students WHERE (status=?) " +
+			"UNION SELECT id,name,status FROM students WHERE (status=?)",
+		"WITH recent AS (SELECT id,name,status FROM students) " +
+			"SELECT id,name,status FROM students WHERE (status=?) UNION ALL SELECT id,name,status FROM students WHERE (status=?)",
+	}
+	stmts := GetRecorder(ctx).Statements()
+	if len(stmts) != len(want) {
+		t.Fatalf("Statements() = %v", stmts)
+	}
+	for i, stmt := range stmts {
+		if stmt.SQL != want[i] {
+			t.Errorf("Statements()[%d] = `%v`, want `%v`", i, stmt.SQL, want[i])
+		}
+	}
+}
@@ -44,12 +44,24 @@ type dbContext struct {
 
 	// tx be open count
 	openCount int
+
+	// dialect of conn, nil means not set
+	dialect Dialect
+}
+
+type connOpt func(*dbContext)
+
+// ConnDialect sets the dialect of conn, which is used by CURD without dialect set by WithDialect
+func ConnDialect(dialect Dialect) connOpt {
+	return func(dc *dbContext) {
+		dc.dialect = dialect
+	}
 }
 
 // WithConn will make sure context carray the same conn
 // if context carray conn, do nothing, return old context and nil
 // otherwis, try to get conn and create one new context
-func WithConn(ctx context.Context, connFactory func() (conn Conn, err error)) (context.Context, error) {
+func WithConn(ctx context.Context, connFactory func() (conn Conn, err error), opts ...connOpt) (context.Context, error) {
 	dbCtx := GetExecutor(ctx)
 	if dbCtx != nil {
 		return ctx, nil
@@ -60,9 +72,14 @@ func WithConn(ctx context.Context, connFactory func() (conn Conn, err error)) (c
 		return nil, err
 	}
 
-	return context.WithValue(ctx, _dbCtxKey, &dbContext{
+	hc := &dbContext{
 		conn: conn,
-	}), nil
+	}
+	for _, opt := range opts {
+		opt(hc)
+	}
+
+	return context.WithValue(ctx, _dbCtxKey, hc), nil
 }
 
 // GetExecutor will return tx at first if exist, or return conn, or return nil
@@ -79,6 +96,22 @@ func GetExecutor(ctx context.Context) Executor {
 	return hc.conn
 }
 
+// inTx reports whether ctx carries an open transaction
+func inTx(ctx context.Context) bool {
+	hc, ok := ctx.Value(_dbCtxKey).(*dbContext)
+	return ok && hc.tx != nil
+}
+
+// getConnDialect returns the dialect of conn, or nil if not set
+func getConnDialect(ctx context.Context) Dialect {
+	hc, ok := ctx.Value(_dbCtxKey).(*dbContext)
+	if !ok {
+		return nil
+	}
+
+	return hc.dialect
+}
+
 func QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	executor := GetExecutor(ctx)
 	if executor == nil {
@@ -3,7 +3,9 @@ package sqlmy
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"math"
+	"reflect"
 	"time"
 
 	"github.com/liuximu/sqlmy/internal"
@@ -17,11 +19,18 @@ const (
 	InsertTypeReplaceInsert InsertType = 2
 )
 
+// ByteUnmarshaler is implemented by the field types which are scanned from the bytes of a column, like JSON
+type ByteUnmarshaler = internal.ByteUnmarshaler
+
 type curdOpt func(*curdOption)
 
 type curdOption struct {
 	queryBuilder func(table string, fields []string, where any) (sql string, args []any, err error)
 	fields       []string
+	exprs        []SelectExpr
+	selectAll    bool
+	// columns are the columns of Data, see withColumns
+	columns []string
 
 	updateBuilder func(table string, where, assign any) (sql string, args []any, err error)
 
@@ -29,9 +38,34 @@ type curdOption struct {
 	insertType    InsertType
 	batchSize     int
 
+	updateBatchSize int
+
+	// maxPlaceholders and maxPacketSize are the budget of one sql of InsertList and UpdateList
+	maxPlaceholders int
+	maxPacketSize   int
+
+	// atomic and continueOnErr are the modes of the batches of InsertList and UpdateList
+	atomic        bool
+	continueOnErr bool
+
 	deleteBuilder func(table string, where any) (sql string, args []any, err error)
 
 	rowsScan func(rs *sql.Rows, target interface{}) error
+
+	comment    *SQLComment
+	commentSet bool
+
+	dialect         Dialect
+	conflictColumns []string
+	returningColumn string
+
+	lockMode internal.LockMode
+	lockWait internal.LockWait
+
+	hints internal.Hints
+
+	// builder is the default builder, and builds the aggregate queries
+	builder *internal.Builder
 }
 
 func WithQueryBuilder(builder func(table string, fields []string, where any) (sql string, args []any, err error)) curdOpt {
@@ -46,6 +80,21 @@ func WithSelectFileds(fields ...string) curdOpt {
 	}
 }
 
+// WithSelectExprs appends the computed columns like `As("COUNT(*)", "cnt")` to the selected fields,
+// the alias of SelectExpr must be the column of Data
+func WithSelectExprs(exprs ...SelectExpr) curdOpt {
+	return func(co *curdOption) {
+		co.exprs = exprs
+	}
+}
+
+// WithSelectAll selects `*` instead of the columns of Data when no field is set by WithSelectFileds
+func WithSelectAll() curdOpt {
+	return func(co *curdOption) {
+		co.selectAll = true
+	}
+}
+
 func WithUpdateBuilder(builder func(table string, where, assign any) (sql string, args []any, err error)) curdOpt {
 	return func(co *curdOption) {
 		co.updateBuilder = builder
@@ -70,35 +119,153 @@ func WithInsertBatchSize(batchSize int) curdOpt {
 	}
 }
 
+// defaultMaxPacketSize is the default max_allowed_packet of MySQL 5.7
+const defaultMaxPacketSize = 4 << 20
+
+// WithMaxPlaceholders sets the max placeholders of one sql of InsertList and UpdateList, the default is the limit of dialect
+func WithMaxPlaceholders(n int) curdOpt {
+	return func(co *curdOption) {
+		co.maxPlaceholders = n
+	}
+}
+
+// WithMaxPacketSize sets the max estimated bytes of one sql of InsertList and UpdateList, default is 4MB
+func WithMaxPacketSize(size int) curdOpt {
+	return func(co *curdOption) {
+		co.maxPacketSize = size
+	}
+}
+
+// batches splits datas by the batch size and the budget of placeholders and packet size,
+// scale multiplies the estimated cost of one row for the sql, the row over budget is a batch itself
+func (co *curdOption) batches(datas []any, batchSize int, scale int) [][2]int {
+	if batchSize <= 0 {
+		batchSize = math.MaxInt
+	}
+	maxPlaceholders := co.maxPlaceholders
+	if maxPlaceholders <= 0 {
+		maxPlaceholders = co.dialect.MaxPlaceholders()
+	}
+	maxPacketSize := co.maxPacketSize
+	if maxPacketSize <= 0 {
+		maxPacketSize = math.MaxInt
+	}
+	return internal.Batches(len(datas), batchSize, maxPlaceholders, maxPacketSize, func(i int) (int, int) {
+		placeholders, size := internal.InsertCost(datas[i])
+		return placeholders * scale, size * scale
+	})
+}
+
+// WithUpdateBatchSize sets the max rows of one update sql of UpdateList
+func WithUpdateBatchSize(batchSize int) curdOpt {
+	return func(co *curdOption) {
+		co.updateBatchSize = batchSize
+	}
+}
+
 func WithDeleteBuilder(builder func(table string, where any) (sql string, args []any, err error)) curdOpt {
 	return func(co *curdOption) {
 		co.deleteBuilder = builder
 	}
 }
 
-var defaultInsertInsert = func(table string, typ InsertType, datas ...any) (sql string, args []any, err error) {
-	return internal.BuildInsert(table, int(typ), datas...)
+var allFileds = []string{"*"}
+
+// withColumns sets the columns of Data, they are selected by default,
+// and the columns of order by, group by and having must be in them
+func withColumns(columns []string) curdOpt {
+	if len(columns) == 0 {
+		columns = nil
+	}
+	return func(co *curdOption) {
+		co.columns = columns
+	}
 }
 
-var allFileds = []string{"*"}
+// selectFields returns the fields set by WithSelectFileds, or the columns of Data, or `*`,
+// followed by the SelectExprs set by WithSelectExprs
+func (co *curdOption) selectFields() ([]string, error) {
+	if len(co.exprs) == 0 {
+		if len(co.fields) > 0 {
+			return co.fields, nil
+		}
+		if co.selectAll || len(co.columns) == 0 {
+			return allFileds, nil
+		}
+		return co.columns, nil
+	}
 
-func newCURDOption(opts ...curdOpt) *curdOption {
-	option := &curdOption{
-		queryBuilder: internal.BuildQuery,
-		fields:       allFileds,
+	aliases := make(map[string]bool, len(co.exprs))
+	for _, expr := range co.exprs {
+		aliases[expr.Alias] = true
+	}
+	var fields []string
+	switch {
+	case len(co.fields) > 0:
+		fields = append(fields, co.fields...)
+	case co.selectAll || len(co.columns) == 0:
+		fields = append(fields, allFileds...)
+	default:
+		// the columns computed by exprs are not selected from the table
+		for _, column := range co.columns {
+			if !aliases[column] {
+				fields = append(fields, column)
+			}
+		}
+	}
+	for _, expr := range co.exprs {
+		field, err := expr.Field(co.columns)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
 
-		insertBuilder: defaultInsertInsert,
-		batchSize:     math.MaxInt,
+func newCURDOption(ctx context.Context, opts ...curdOpt) *curdOption {
+	option := &curdOption{
+		batchSize:       math.MaxInt,
+		updateBatchSize: math.MaxInt,
+		maxPacketSize:   defaultMaxPacketSize,
 
 		rowsScan: internal.Scan,
 
-		deleteBuilder: internal.BuildDelete,
-		updateBuilder: internal.BuildUpdate,
+		returningColumn: "id",
 	}
 	for _, opt := range opts {
 		opt(option)
 	}
 
+	if option.dialect == nil {
+		option.dialect = getConnDialect(ctx)
+	}
+	if option.dialect == nil {
+		option.dialect = MySQL
+	}
+
+	builder := &internal.Builder{
+		Dialect:         option.dialect,
+		ConflictColumns: option.conflictColumns,
+		Columns:         option.columns,
+		Hints:           &option.hints,
+	}
+	option.builder = builder
+	if option.queryBuilder == nil {
+		option.queryBuilder = builder.BuildQuery
+	}
+	if option.insertBuilder == nil {
+		option.insertBuilder = func(table string, typ InsertType, datas ...any) (sql string, args []any, err error) {
+			return builder.BuildInsert(table, int(typ), datas...)
+		}
+	}
+	if option.deleteBuilder == nil {
+		option.deleteBuilder = builder.BuildDelete
+	}
+	if option.updateBuilder == nil {
+		option.updateBuilder = builder.BuildUpdate
+	}
+
 	return option
 }
 
@@ -110,23 +277,31 @@ type CURDer[Data, Param any] interface {
 	InsertList(ctx context.Context, datas []*Param, opts ...curdOpt) (lastInsertedID int64, err error)
 
 	Update(ctx context.Context, where *Param, data *Param, opts ...curdOpt) (affectedRows int64, err error)
+	UpdateList(ctx context.Context, keyColumn string, datas []*Param, opts ...curdOpt) (affectedRows int64, err error)
 
 	Delete(ctx context.Context, where *Param, opts ...curdOpt) (affectedRows int64, err error)
 }
 
 type CURD[Data, Param any] struct {
 	table string
+	opts  []curdOpt
 }
 
-func NewCURD[Data, Param any](tableName string) *CURD[Data, Param] {
+// NewCURD creates the CURD of table, opts are the default options of every method,
+// which can be overwritten by the options passed to method
+func NewCURD[Data, Param any](tableName string, opts ...curdOpt) *CURD[Data, Param] {
 	return &CURD[Data, Param]{
 		table: tableName,
+		opts:  opts,
 	}
 
 }
 
-func argsDeal(args []any) []any {
-	return args
+func (curd *CURD[Data, Param]) newOption(ctx context.Context, opts ...curdOpt) *curdOption {
+	all := make([]curdOpt, 0, 1+len(curd.opts)+len(opts))
+	all = append(all, withColumns(internal.Columns(reflect.TypeOf((*Data)(nil)))))
+	all = append(all, curd.opts...)
+	return newCURDOption(ctx, append(all, opts...)...)
 }
 
 func costMs(begin time.Time) int64 {
@@ -149,20 +324,30 @@ func (curd *CURD[Data, Param]) Query(ctx context.Context, param *Param, opts ...
 
 func (curd *CURD[Data, Param]) QueryList(ctx context.Context, param *Param, opts ...curdOpt) ([]*Data, error) {
 	begin := time.Now()
-	option := newCURDOption(opts...)
+	option := curd.newOption(ctx, opts...)
 
-	query, args, err := option.queryBuilder(curd.table, option.fields, param)
+	fields, err := option.selectFields()
+	var query string
+	var args []any
+	if err == nil {
+		query, args, err = option.queryBuilder(curd.table, fields, param)
+	}
+	if err == nil {
+		query, err = option.lockSQL(ctx, query)
+	}
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [QueryBuild] sql[%s] args[%v] err[%v]", costMs(begin), query, argsDeal(args), err)
+		logger.Error(ctx, "cost[%d] [QueryBuild] table[%s] err[%v]", costMs(begin), curd.table, err)
 		return nil, err
 	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[QueryBuild]", query, args)
 
-	rows, err := QueryContext(ctx, query, args...)
+	rows, err := curdQuery(ctx, query, args)
 	if err == sql.ErrNoRows {
 		err = nil
 	}
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [QueryQuery] err[%v]", costMs(begin), err)
+		logger.Error(ctx, "cost[%d] [QueryQuery] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 		return nil, err
 	}
 
@@ -172,12 +357,12 @@ func (curd *CURD[Data, Param]) QueryList(ctx context.Context, param *Param, opts
 		err = option.rowsScan(rows, &datas)
 
 		if err != nil {
-			logger.Error(ctx, "cost[%d] [QueryScan] err[%v]", costMs(begin), err)
+			logger.Error(ctx, "cost[%d] [QueryScan] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 			return nil, err
 		}
 	}
 
-	logger.Info(ctx, "cost[%d] [QuerySucc] sql[%s] args[%v] len[%d]", costMs(begin), query, argsDeal(args), len(datas))
+	logger.Info(ctx, "cost[%d] [QuerySucc] table[%s] len[%d]", costMs(begin), curd.table, len(datas))
 	return datas, nil
 }
 
@@ -185,99 +370,241 @@ func (curd *CURD[Data, Param]) Insert(ctx context.Context, data *Param, opts ...
 	return curd.InsertList(ctx, []*Param{data}, opts...)
 }
 
+// InsertList inserts datas in batches and returns the id got from the last batch, see InsertBatches
 func (curd *CURD[Data, Param]) InsertList(ctx context.Context, datas []*Param, opts ...curdOpt) (lastInsertedID int64, err error) {
+	results, err := curd.InsertBatches(ctx, datas, opts...)
+	if err != nil || len(results) == 0 {
+		return 0, err
+	}
+	return results[len(results)-1].insertID, nil
+}
+
+// InsertBatchResult is the result of one insert sql of InsertBatches
+type InsertBatchResult struct {
+	// Rows is the number of datas of the batch
+	Rows         int
+	RowsAffected int64
+	// FirstInsertedID and LastInsertedID are the ids of the first and last inserted rows,
+	// they are got by `RETURNING` for Postgres, or computed from LastInsertId and Rows,
+	// which assumes the ids of one sql are consecutive. For the ignore and replace inserts
+	// of MySQL and SQLite, both of them are LastInsertId as the ids of the other rows are unknown
+	FirstInsertedID int64
+	LastInsertedID  int64
+	// Err is the error of the batch
+	Err error
+
+	// insertID is the id InsertList returns: LastInsertId or the last returned id
+	insertID int64
+}
+
+// InsertBatches inserts datas in batches and returns the result of every batch,
+// a batch has WithInsertBatchSize rows at most, and is split when its placeholders
+// or estimated size exceed WithMaxPlaceholders or WithMaxPacketSize.
+// It stops at the failed batch whose result is the last one, or goes on with WithContinueOnError
+// and returns BatchError. WithAtomic inserts all batches in one transaction
+func (curd *CURD[Data, Param]) InsertBatches(ctx context.Context, datas []*Param, opts ...curdOpt) (results []InsertBatchResult, err error) {
 	if len(datas) == 0 { // what are U doing...
-		return
+		return nil, nil
 	}
 
 	begin := time.Now()
-	option := newCURDOption(opts...)
-
-	var rst sql.Result
-	for i := 0; i <= len(datas)/option.batchSize; i++ {
-		a := i * option.batchSize
-		b := (i + 1) * option.batchSize
-		if b > len(datas) {
-			b = len(datas)
-		}
+	option := curd.newOption(ctx, opts...)
 
-		tmp := make([]any, 0, b-a)
-		for i := a; i < b; i++ {
-			tmp = append(tmp, datas[i])
+	all := make([]any, 0, len(datas))
+	for _, data := range datas {
+		all = append(all, data)
+	}
+	batches := option.batches(all, option.batchSize, 1)
+	err = option.atomically(ctx, func(ctx context.Context) error {
+		results = make([]InsertBatchResult, 0, len(batches))
+		batchErr := &BatchError{}
+		for i, batch := range batches {
+			result := curd.insertBatch(ctx, option, begin, i, all[batch[0]:batch[1]])
+			results = append(results, result)
+			if result.Err == nil {
+				continue
+			}
+			if !option.continueOnError() {
+				return result.Err
+			}
+			batchErr.add(i, result.Err)
 		}
-		query, args, err := option.insertBuilder(curd.table, option.insertType, tmp...)
+		return batchErr.err()
+	})
+	return results, err
+}
+
+// insertBatch inserts the i-th batch
+func (curd *CURD[Data, Param]) insertBatch(ctx context.Context, option *curdOption, begin time.Time, i int, datas []any) (result InsertBatchResult) {
+	result.Rows = len(datas)
+	query, args, err := option.insertBuilder(curd.table, option.insertType, datas...)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [InsertBuild] [%d] table[%s] err[%v]", costMs(begin), i, curd.table, err)
+		result.Err = err
+		return
+	}
+	if !option.dialect.SupportLastInsertID() {
+		query += " RETURNING " + option.dialect.Quote(option.returningColumn)
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, fmt.Sprintf("[InsertBuild] [%d]", i), query, args)
+
+	if option.dialect.SupportLastInsertID() {
+		rst, err := curdExec(ctx, query, args)
 		if err != nil {
-			logger.Error(ctx, "cost[%d] [UpdateBuild] [i] sql[%s] args[%v] err[%v]", costMs(begin), i, query, argsDeal(args), err)
-			return 0, err
+			logger.Error(ctx, "cost[%d] [InsertExec] [%d] sql[%s] err[%v]", costMs(begin), i, sqlDeal(query), err)
+			result.Err = err
+			return
 		}
 
-		rst, err = ExecContext(ctx, query, args...)
+		result.insertID, err = rst.LastInsertId()
 		if err != nil {
-			logger.Error(ctx, "cost[%d] [UpdateExec] [i] sql[%s] args[%v] err[%v]", costMs(begin), i, query, argsDeal(args), err)
-			return 0, err
+			logger.Error(ctx, "cost[%d] [InsertLastInsertID] [%d] table[%s] err[%v]", costMs(begin), i, curd.table, err)
+			result.Err = err
+			return
 		}
-
-		logger.Info(ctx, "cost[%d] [QuerySucc] [i] sql[%s] args[%v] len[%d]", costMs(begin), query, argsDeal(args), len(datas))
-	}
-
-	id, err := rst.LastInsertId()
-	if err != nil {
-		logger.Error(ctx, "cost[%d] [UpdateLastInsertID] last_id[%d] err[%v]", costMs(begin), id, err)
-		return 0, err
+		result.RowsAffected, err = rst.RowsAffected()
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [InsertRowsAffected] [%d] table[%s] err[%v]", costMs(begin), i, curd.table, err)
+			result.Err = err
+			return
+		}
+		if option.insertType == InsertTypeCommonInsert {
+			result.FirstInsertedID, result.LastInsertedID = option.dialect.InsertedIDs(result.insertID, int64(result.Rows))
+		} else {
+			// the ids of the replaced and ignored rows are unknown
+			result.FirstInsertedID, result.LastInsertedID = result.insertID, result.insertID
+		}
+	} else {
+		result.FirstInsertedID, result.LastInsertedID, result.RowsAffected, err = curdExecReturning(ctx, query, args)
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [InsertExec] [%d] sql[%s] err[%v]", costMs(begin), i, sqlDeal(query), err)
+			result.Err = err
+			return
+		}
+		result.insertID = result.LastInsertedID
 	}
 
-	return id, nil
+	logger.Info(ctx, "cost[%d] [InsertSucc] [%d] table[%s] len[%d]", costMs(begin), i, curd.table, len(datas))
+	return
 }
 
 func (curd *CURD[Data, Param]) Update(ctx context.Context, where *Param, assign *Param, opts ...curdOpt) (affectedRows int64, err error) {
 	begin := time.Now()
-	option := newCURDOption(opts...)
+	option := curd.newOption(ctx, opts...)
 
 	query, args, err := option.updateBuilder(curd.table, where, assign)
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [UpdateBuild] sql[%s] args[%v] err[%v]", costMs(begin), query, argsDeal(args), err)
+		logger.Error(ctx, "cost[%d] [UpdateBuild] table[%s] err[%v]", costMs(begin), curd.table, err)
 		return 0, err
 	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[UpdateBuild]", query, args)
 
-	rst, err := ExecContext(ctx, query, args...)
+	rst, err := curdExec(ctx, query, args)
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [UpdateExec] err[%v]", costMs(begin), err)
+		logger.Error(ctx, "cost[%d] [UpdateExec] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 		return 0, err
 	}
 
 	affectedRows, err = rst.RowsAffected()
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [UpdateRowsAffected] err[%v]", costMs(begin), err)
+		logger.Error(ctx, "cost[%d] [UpdateRowsAffected] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 		return 0, err
 	}
 
-	logger.Info(ctx, "cost[%d] [UpdateSucc] sql[%s] args[%v] rows[%d]", costMs(begin), query, argsDeal(args), affectedRows)
+	logger.Info(ctx, "cost[%d] [UpdateSucc] table[%s] rows[%d]", costMs(begin), curd.table, affectedRows)
 	return affectedRows, nil
 
 }
 
+// UpdateList updates every row identified by keyColumn with its own values of datas,
+// the sql is like `UPDATE t SET a=CASE id WHEN 1 THEN 'a1' WHEN 2 THEN 'a2' ELSE a END WHERE id IN (1,2)`,
+// the rows are updated in batches by WithUpdateBatchSize, affectedRows is the total of the succeeded batches.
+// Like InsertBatches, it works with WithAtomic and WithContinueOnError
+func (curd *CURD[Data, Param]) UpdateList(ctx context.Context, keyColumn string, datas []*Param, opts ...curdOpt) (affectedRows int64, err error) {
+	if len(datas) == 0 {
+		return
+	}
+
+	begin := time.Now()
+	option := curd.newOption(ctx, opts...)
+
+	all := make([]any, 0, len(datas))
+	for _, data := range datas {
+		all = append(all, data)
+	}
+	// every column of a row takes `WHEN ? THEN ?`, and the key is in `IN (?)`
+	batches := option.batches(all, option.updateBatchSize, 2)
+	err = option.atomically(ctx, func(ctx context.Context) error {
+		affectedRows = 0
+		batchErr := &BatchError{}
+		for i, batch := range batches {
+			rows, err := curd.updateBatch(ctx, option, begin, i, keyColumn, all[batch[0]:batch[1]])
+			affectedRows += rows
+			if err == nil {
+				continue
+			}
+			if !option.continueOnError() {
+				return err
+			}
+			batchErr.add(i, err)
+		}
+		return batchErr.err()
+	})
+	return affectedRows, err
+}
+
+// updateBatch updates the i-th batch of UpdateList
+func (curd *CURD[Data, Param]) updateBatch(ctx context.Context, option *curdOption, begin time.Time, i int, keyColumn string, datas []any) (int64, error) {
+	query, args, err := option.builder.BuildUpdateList(curd.table, keyColumn, datas...)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [UpdateListBuild] [%d] table[%s] err[%v]", costMs(begin), i, curd.table, err)
+		return 0, err
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, fmt.Sprintf("[UpdateListBuild] [%d]", i), query, args)
+
+	rst, err := curdExec(ctx, query, args)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [UpdateListExec] [%d] sql[%s] err[%v]", costMs(begin), i, sqlDeal(query), err)
+		return 0, err
+	}
+
+	rows, err := rst.RowsAffected()
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [UpdateListRowsAffected] [%d] sql[%s] err[%v]", costMs(begin), i, sqlDeal(query), err)
+		return 0, err
+	}
+
+	logger.Info(ctx, "cost[%d] [UpdateListSucc] [%d] table[%s] len[%d] rows[%d]", costMs(begin), i, curd.table, len(datas), rows)
+	return rows, nil
+}
+
 func (curd *CURD[Data, Param]) Delete(ctx context.Context, where *Param, opts ...curdOpt) (affectedRows int64, err error) {
 	begin := time.Now()
-	option := newCURDOption(opts...)
+	option := curd.newOption(ctx, opts...)
 
 	query, args, err := option.deleteBuilder(curd.table, where)
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [DeleteBuild] sql[%s] args[%v] err[%v]", costMs(begin), query, argsDeal(args), err)
+		logger.Error(ctx, "cost[%d] [DeleteBuild] table[%s] err[%v]", costMs(begin), curd.table, err)
 		return 0, err
 	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[DeleteBuild]", query, args)
 
-	rst, err := ExecContext(ctx, query, args...)
+	rst, err := curdExec(ctx, query, args)
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [DeleteExec] err[%v]", costMs(begin), err)
+		logger.Error(ctx, "cost[%d] [DeleteExec] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 		return 0, err
 	}
 
 	affectedRows, err = rst.RowsAffected()
 	if err != nil {
-		logger.Error(ctx, "cost[%d] [DeleteRowsAffected] err[%v]", costMs(begin), err)
+		logger.Error(ctx, "cost[%d] [DeleteRowsAffected] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
 		return 0, err
 	}
 
-	logger.Info(ctx, "cost[%d] [DeleteSucc] sql[%s] args[%v] rows[%d]", costMs(begin), query, argsDeal(args), affectedRows)
+	logger.Info(ctx, "cost[%d] [DeleteSucc] table[%s] rows[%d]", costMs(begin), curd.table, affectedRows)
 	return affectedRows, nil
 }
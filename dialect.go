@@ -0,0 +1,40 @@
+package sqlmy
+
+import "github.com/liuximu/sqlmy/internal"
+
+// Dialect is the sql difference between databases
+type Dialect = internal.Dialect
+
+var (
+	// MySQL is the default dialect
+	MySQL Dialect = internal.MySQL
+	// Postgres uses `$N` placeholders and double-quoted identifiers,
+	// ignore and replace insert are done by `ON CONFLICT`,
+	// and InsertList gets the inserted id by `RETURNING`
+	Postgres Dialect = internal.Postgres
+	// SQLite uses double-quoted identifiers and `INSERT OR IGNORE`/`INSERT OR REPLACE`,
+	// replace insert with conflict columns is upsert by `ON CONFLICT`
+	SQLite Dialect = internal.SQLite
+)
+
+// WithDialect sets the dialect, usually it's passed to NewCURD
+// it takes precedence over the dialect of conn set by ConnDialect
+func WithDialect(dialect Dialect) curdOpt {
+	return func(co *curdOption) {
+		co.dialect = dialect
+	}
+}
+
+// WithConflictColumns sets the conflict target of replace insert for the dialect which needs it, like Postgres
+func WithConflictColumns(columns ...string) curdOpt {
+	return func(co *curdOption) {
+		co.conflictColumns = columns
+	}
+}
+
+// WithReturningColumn sets the column returned by insert for the dialect not supporting LastInsertId, default is `id`
+func WithReturningColumn(column string) curdOpt {
+	return func(co *curdOption) {
+		co.returningColumn = column
+	}
+}
@@ -0,0 +1,61 @@
+package sqlmy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var PGStudentCURD = NewCURD[Student, StudentParam]("students", WithDialect(Postgres))
+
+func ExampleWithDialect() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectQuery(`SELECT "id","name","status" FROM "students" WHERE ("id"=$1)`).
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 2),
+		)
+	mock.
+		ExpectQuery(`INSERT INTO "students" ("id","name") VALUES ($1,$2),($3,$4) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name" RETURNING "id"`).
+		WithArgs(2, "n2", 3, "n3").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	student, err := PGStudentCURD.Query(ctx, &StudentParam{
+		ID: P(int64(1)),
+	})
+	fmt.Println(err)
+	if student != nil {
+		fmt.Println(student.ID, student.Name, student.Status)
+	}
+
+	id, err := PGStudentCURD.InsertList(ctx, []*StudentParam{
+		{
+			ID:   P(int64(2)),
+			Name: P("n2"),
+		},
+		{
+			ID:   P(int64(3)),
+			Name: P("n3"),
+		},
+	}, WithInsertType(InsertTypeReplaceInsert), WithConflictColumns("id"))
+	fmt.Println(id, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: <nil>
+	// 1 N1 2
+	// 3 <nil>
+}
@@ -0,0 +1,145 @@
+package sqlmy
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// Statement is one sql recorded in dry run
+type Statement struct {
+	SQL  string
+	Args []any
+
+	// Executed is true if the statement was sent to database
+	Executed bool
+
+	// rawArgs keeps the sensitive marks of Args
+	rawArgs []any
+}
+
+// String returns the sql interpolated with args, the sensitive args are masked
+func (s Statement) String() string {
+	return sqlRender(s.SQL, s.rawArgs)
+}
+
+// Recorder collects the statements issued by CURD in dry run
+type Recorder struct {
+	mu         sync.Mutex
+	statements []Statement
+
+	execQuery bool
+}
+
+// Statements returns the recorded statements in order
+func (r *Recorder) Statements() []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Statement(nil), r.statements...)
+}
+
+// Reset drops all the recorded statements
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = nil
+}
+
+func (r *Recorder) record(query string, args []any, executed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statements = append(r.statements, Statement{
+		SQL:      query,
+		Args:     internal.Unwrap(args),
+		Executed: executed,
+		rawArgs:  args,
+	})
+}
+
+type dryRunOpt func(*Recorder)
+
+// DryRunExecQuery makes the read sql still be executed in dry run
+func DryRunExecQuery() dryRunOpt {
+	return func(r *Recorder) {
+		r.execQuery = true
+	}
+}
+
+type dryRunKey int
+
+var _dryRunKey dryRunKey
+
+// WithDryRun returns a context in which CURD builds sql but doesn't execute them
+// the sql are recorded into the Recorder got by GetRecorder,
+// write methods return zero results, read methods return empty results unless DryRunExecQuery
+func WithDryRun(ctx context.Context, opts ...dryRunOpt) context.Context {
+	r := &Recorder{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return context.WithValue(ctx, _dryRunKey, r)
+}
+
+// GetRecorder returns the Recorder of dry run, or nil if not in dry run
+func GetRecorder(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(_dryRunKey).(*Recorder)
+	return r
+}
+
+type dryRunResult struct{}
+
+func (dryRunResult) LastInsertId() (int64, error) { return 0, nil }
+func (dryRunResult) RowsAffected() (int64, error) { return 0, nil }
+
+// curdQuery executes the read sql built by CURD
+func curdQuery(ctx context.Context, query string, args []any) (*sql.Rows, error) {
+	if r := GetRecorder(ctx); r != nil {
+		r.record(query, args, r.execQuery)
+		if !r.execQuery {
+			return nil, nil
+		}
+	}
+
+	return QueryContext(ctx, query, internal.Unwrap(args)...)
+}
+
+// curdExec executes the write sql built by CURD
+func curdExec(ctx context.Context, query string, args []any) (sql.Result, error) {
+	if r := GetRecorder(ctx); r != nil {
+		r.record(query, args, false)
+		return dryRunResult{}, nil
+	}
+
+	return ExecContext(ctx, query, internal.Unwrap(args)...)
+}
+
+// curdExecReturning executes the write sql with `RETURNING` and returns the first and last returned values and their count
+func curdExecReturning(ctx context.Context, query string, args []any) (first, last, n int64, err error) {
+	if r := GetRecorder(ctx); r != nil {
+		r.record(query, args, false)
+		return 0, 0, 0, nil
+	}
+
+	rows, err := QueryContext(ctx, query, internal.Unwrap(args)...)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&last); err != nil {
+			return 0, 0, 0, err
+		}
+		if n == 0 {
+			first = last
+		}
+		n++
+	}
+	return first, last, n, rows.Err()
+}
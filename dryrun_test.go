@@ -0,0 +1,129 @@
+package sqlmy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func ExampleWithDryRun() {
+	ctx := WithDryRun(context.Background())
+
+	affectedRows, err := StudentCURD.Update(ctx, &StudentParam{
+		ID: P(int64(1)),
+	}, &StudentParam{
+		Name: P("n1"),
+	})
+	fmt.Println(affectedRows, err)
+
+	for _, stmt := range GetRecorder(ctx).Statements() {
+		fmt.Println(stmt)
+	}
+
+	// output: 0 <nil>
+	// UPDATE students SET name='n1' WHERE (id=1)
+}
+
+func TestDryRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 2))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = WithDryRun(ctx, DryRunExecQuery())
+	student, err := StudentCURD.Query(ctx, &StudentParam{ID: P(int64(1))})
+	if err != nil || student == nil || student.Name != "N1" {
+		t.Fatalf("Query() = %v, %v", student, err)
+	}
+
+	id, err := StudentCURD.Insert(ctx, &StudentParam{ID: P(int64(2)), Name: P("n2")})
+	if err != nil || id != 0 {
+		t.Fatalf("Insert() = %v, %v", id, err)
+	}
+
+	rows, err := StudentCURD.Delete(ctx, &StudentParam{IDs: []int64{1, 2}})
+	if err != nil || rows != 0 {
+		t.Fatalf("Delete() = %v, %v", rows, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	want := []Statement{
+		{SQL: "SELECT id,name,status FROM students WHERE (id=?)", Args: []any{int64(1)}, Executed: true},
+		{SQL: "INSERT INTO students (id,name) VALUES (?,?)", Args: []any{int64(2), "n2"}},
+		{SQL: "DELETE FROM students WHERE (id IN (?,?))", Args: []any{int64(1), int64(2)}},
+	}
+	got := GetRecorder(ctx).Statements()
+	for i := range got {
+		got[i].rawArgs = nil
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %v, want %v", got, want)
+	}
+}
+
+func TestDryRunSkipQuery(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	students, err := StudentCURD.QueryList(ctx, &StudentParam{ID: P(int64(1))})
+	if err != nil || len(students) != 0 {
+		t.Fatalf("QueryList() = %v, %v", students, err)
+	}
+
+	stmts := GetRecorder(ctx).Statements()
+	if len(stmts) != 1 || stmts[0].Executed {
+		t.Errorf("Statements() = %v", stmts)
+	}
+
+	GetRecorder(ctx).Reset()
+	if stmts := GetRecorder(ctx).Statements(); len(stmts) != 0 {
+		t.Errorf("Statements() after Reset = %v", stmts)
+	}
+	if GetRecorder(context.Background()) != nil {
+		t.Errorf("GetRecorder() without dry run should be nil")
+	}
+}
+
+func TestDryRunDefaultFields(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	// Param as Data, its special keys are not selected nor allowed in order by
+	paramCURD := NewCURD[StudentParam, StudentParam]("students")
+	if _, err := paramCURD.QueryList(ctx, &StudentParam{Limit: []uint{10}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := paramCURD.QueryList(ctx, &StudentParam{OrderBy: P("_limit desc")}); err == nil {
+		t.Error("QueryList() order by _limit err = nil, want error")
+	}
+
+	want := "SELECT id,name,status FROM students LIMIT ?,?"
+	if got := GetRecorder(ctx).Statements()[0].SQL; got != want {
+		t.Errorf("QueryList() sql = `%v`, want `%v`", got, want)
+	}
+}
+
+func TestDryRunPostgresString(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	if _, err := PGStudentCURD.Update(ctx, &StudentParam{ID: P(int64(1))}, &StudentParam{Name: P("it's")}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `UPDATE "students" SET "name"='it''s' WHERE ("id"=1)`
+	if got := GetRecorder(ctx).Statements()[0].String(); got != want {
+		t.Errorf("Statement.String() = `%v`, want `%v`", got, want)
+	}
+}
@@ -26,14 +26,14 @@ type StudentParam struct {
 	Limit   []uint  `db:"_limit"`
 }
 
-func ExampleQuery() {
+func ExampleCURD_Query() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
 	}
 
 	mock.
-		ExpectQuery(`SELECT \* FROM students`).
+		ExpectQuery(`SELECT id,name,status FROM students`).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name", "status"}).
 				AddRow(1, "N1", 2).
@@ -58,14 +58,14 @@ func ExampleQuery() {
 	// 1 N1 2
 }
 
-func ExampleQuery1() {
+func ExampleCURD_Query_where() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
 	}
 
 	mock.
-		ExpectQuery(`SELECT \* FROM students WHERE \(id=\?\)`).
+		ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\)`).
 		WithArgs(1).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 2),
@@ -91,7 +91,7 @@ func ExampleQuery1() {
 	// 1 N1 2
 }
 
-func ExampleQuery2() {
+func ExampleCURD_Query_selectFields() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -124,14 +124,14 @@ func ExampleQuery2() {
 	// 0 N1 0
 }
 
-func ExampleQueryList() {
+func ExampleCURD_QueryList() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
 	}
 
 	mock.
-		ExpectQuery(`SELECT \* FROM students WHERE \(id=\?\)`).
+		ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\)`).
 		WithArgs(1).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name", "status"}).
@@ -161,7 +161,42 @@ func ExampleQueryList() {
 	// 2 N2 3
 }
 
-func ExampleInsert() {
+func ExampleCURD_QueryList_selectAll() {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectQuery(`SELECT \* FROM students WHERE \(id=\?\)`).
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status", "added"}).
+				AddRow(1, "N1", 2, "ignored"),
+		)
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	students, err := StudentCURD.QueryList(ctx, &StudentParam{
+		ID: P(int64(1)),
+	}, WithSelectAll())
+	fmt.Println(err)
+	for _, student := range students {
+		fmt.Println(student.ID, student.Name, student.Status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: <nil>
+	// 1 N1 2
+}
+
+func ExampleCURD_Insert() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -188,7 +223,7 @@ func ExampleInsert() {
 	// <nil>
 }
 
-func ExampleInsertIgnore() {
+func ExampleCURD_Insert_ignore() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -215,7 +250,7 @@ func ExampleInsertIgnore() {
 	// <nil>
 }
 
-func ExampleInsertList() {
+func ExampleCURD_InsertList() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -252,7 +287,7 @@ func ExampleInsertList() {
 	// <nil>
 }
 
-func ExampleInsertList1() {
+func ExampleCURD_InsertList_batch() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -293,7 +328,55 @@ func ExampleInsertList1() {
 	// <nil>
 }
 
-func ExampleDelete() {
+func ExampleCURD_InsertBatches() {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\),\(\?,\?\)`).
+		WithArgs(1, "n1", 2, "n2").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.
+		ExpectExec(`INSERT INTO students \(id,name\) VALUES \(\?,\?\),\(\?,\?\)`).
+		WithArgs(3, "n3", 4, "n4").
+		WillReturnResult(sqlmock.NewResult(3, 2))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	results, err := StudentCURD.InsertBatches(ctx, []*StudentParam{
+		{
+			ID:   P(int64(1)),
+			Name: P("n1"),
+		},
+		{
+			ID:   P(int64(2)),
+			Name: P("n2"),
+		},
+		{
+			ID:   P(int64(3)),
+			Name: P("n3"),
+		},
+		{
+			ID:   P(int64(4)),
+			Name: P("n4"),
+		},
+	}, WithMaxPlaceholders(5))
+
+	for _, result := range results {
+		fmt.Println(result.Rows, result.RowsAffected, result.FirstInsertedID, result.LastInsertedID)
+	}
+	fmt.Println(err)
+	// output: 2 2 1 2
+	// 2 2 3 4
+	// <nil>
+}
+
+func ExampleCURD_Delete() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -319,7 +402,7 @@ func ExampleDelete() {
 	// <nil>
 }
 
-func ExampleUpdate() {
+func ExampleCURD_Update() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
@@ -347,13 +430,56 @@ func ExampleUpdate() {
 	// <nil>
 }
 
-func ExampleQueryEmpty() {
+func ExampleCURD_UpdateList() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectExec(`UPDATE students SET name=CASE id WHEN ? THEN ? WHEN ? THEN ? ELSE name END,`+
+			`status=CASE id WHEN ? THEN ? ELSE status END WHERE id IN (?,?)`).
+		WithArgs(1, "n1", 2, "n2", 2, 0, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.
+		ExpectExec(`UPDATE students SET name=CASE id WHEN ? THEN ? ELSE name END WHERE id IN (?)`).
+		WithArgs(3, "n3", 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	affectedRows, err := StudentCURD.UpdateList(ctx, "id", []*StudentParam{
+		{
+			ID:   P(int64(1)),
+			Name: P("n1"),
+		},
+		{
+			ID:     P(int64(2)),
+			Name:   P("n2"),
+			Status: P(0),
+		},
+		{
+			ID:   P(int64(3)),
+			Name: P("n3"),
+		},
+	}, WithUpdateBatchSize(2))
+
+	fmt.Println(affectedRows)
+	fmt.Println(err)
+	// output: 3
+	// <nil>
+}
+
+func ExampleCURD_Query_empty() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
 	}
 
-	mock.ExpectQuery(`SELECT \* FROM students WHERE \(id=\?\)`).
+	mock.ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\)`).
 		WithArgs(1).
 		WillReturnError(sql.ErrNoRows)
 
@@ -376,13 +502,13 @@ func ExampleQueryEmpty() {
 	// output: <nil>
 }
 
-func ExampleQueryBad() {
+func ExampleCURD_Query_bad() {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		panic(err)
 	}
 
-	mock.ExpectQuery(`SELECT \* FROM students WHERE \(id=\?\)`).
+	mock.ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\)`).
 		WithArgs(1).
 		WillReturnError(sql.ErrConnDone)
 
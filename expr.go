@@ -0,0 +1,38 @@
+package sqlmy
+
+import "github.com/liuximu/sqlmy/internal"
+
+// Expr is a raw sql expression with its args, a Param's field of type Expr or *Expr is inlined in sql:
+//   - in assign, `column=expr`
+//   - in where, `column op expr`, op can be the comparisons, like and not like
+//   - in where, the field tagged `db:"_expr"` is a whole condition, use keys like `_expr_attrs` for more conditions
+//
+// ExprColumn in the sql is replaced by the column
+type Expr = internal.Expr
+
+// ExprColumn is the placeholder of column in the sql of Expr
+const ExprColumn = internal.ExprColumn
+
+// NewExpr returns the Expr of sql and args, the args are bound by `?`
+func NewExpr(sql string, args ...any) Expr {
+	return Expr{SQL: sql, Args: args}
+}
+
+// Incr returns the Expr increasing the column by n, like `count=count+1`
+func Incr(n any) Expr {
+	return NewExpr(ExprColumn+"+?", n)
+}
+
+// Now returns the Expr of current time, CURRENT_TIMESTAMP is supported by all dialects
+func Now() Expr {
+	return NewExpr("CURRENT_TIMESTAMP")
+}
+
+// SelectExpr is the computed column `SQL AS Alias` selected by WithSelectExprs,
+// like the window function `ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) AS rn`
+type SelectExpr = internal.SelectExpr
+
+// As returns the SelectExpr `sql AS alias`, the alias should be the column of the target struct
+func As(sql, alias string) SelectExpr {
+	return SelectExpr{SQL: sql, Alias: alias}
+}
@@ -4,7 +4,5 @@ go 1.18
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.4.0
-	github.com/didi/gendry v1.3.2
+	github.com/mattn/go-sqlite3 v1.14.16
 )
-
-require github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,34 @@
+package sqlmy
+
+import "github.com/liuximu/sqlmy/internal"
+
+// ErrBadHint is returned if the hint is invalid or the dialect doesn't support hints, only MySQL supports them
+var ErrBadHint = internal.ErrBadHint
+
+// WithForceIndex adds `FORCE INDEX (indexes)` after the table of select and update
+func WithForceIndex(indexes ...string) curdOpt {
+	return withIndexHint(internal.ForceIndex, indexes)
+}
+
+// WithUseIndex adds `USE INDEX (indexes)` after the table of select and update
+func WithUseIndex(indexes ...string) curdOpt {
+	return withIndexHint(internal.UseIndex, indexes)
+}
+
+// WithIgnoreIndex adds `IGNORE INDEX (indexes)` after the table of select and update
+func WithIgnoreIndex(indexes ...string) curdOpt {
+	return withIndexHint(internal.IgnoreIndex, indexes)
+}
+
+func withIndexHint(kind string, indexes []string) curdOpt {
+	return func(co *curdOption) {
+		co.hints.Index = append(co.hints.Index, internal.IndexHint{Kind: kind, Indexes: indexes})
+	}
+}
+
+// WithOptimizerHints adds the optimizer hints like `MAX_EXECUTION_TIME(1000)` as `/*+ ... */` after the verb of select, update and delete
+func WithOptimizerHints(hints ...string) curdOpt {
+	return func(co *curdOption) {
+		co.hints.Optimizer = append(co.hints.Optimizer, hints...)
+	}
+}
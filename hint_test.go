@@ -0,0 +1,98 @@
+package sqlmy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func ExampleWithForceIndex() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectQuery(`SELECT /*+ MAX_EXECUTION_TIME(1000) */ id,name,status FROM students FORCE INDEX (idx_status) WHERE (status=?)`).
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 1),
+		)
+	mock.
+		ExpectExec(`UPDATE students USE INDEX (PRIMARY) SET name=? WHERE (id=?)`).
+		WithArgs("n1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	students, err := StudentCURD.QueryList(ctx, &StudentParam{Status: P(1)},
+		WithForceIndex("idx_status"), WithOptimizerHints("MAX_EXECUTION_TIME(1000)"))
+	fmt.Println(len(students), err)
+
+	affectedRows, err := StudentCURD.Update(ctx, &StudentParam{ID: P(int64(1))}, &StudentParam{Name: P("n1")},
+		WithUseIndex("PRIMARY"))
+	fmt.Println(affectedRows, err)
+
+	_, err = StudentCURD.Delete(ctx, &StudentParam{ID: P(int64(1))}, WithIgnoreIndex("idx_status"))
+	fmt.Println(err)
+
+	_, err = PGStudentCURD.QueryList(ctx, nil, WithOptimizerHints("SeqScan(students)"))
+	fmt.Println(err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: 1 <nil>
+	// 1 <nil>
+	// bad hint: delete doesn't support index hints
+	// bad hint: postgres doesn't support hints
+}
+
+func TestHintsJoinAndCompound(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	type classStudent struct {
+		StudentID int64  `db:"students.id"`
+		ClassName string `db:"classes.name"`
+	}
+	classCURD := NewCURD[Student, StudentParam]("classes")
+	join := NewJoin(StudentCURD.Where(nil)).InnerJoin(classCURD.Where(nil), On("status", "id"))
+	if _, err := QueryJoin[classStudent](ctx, join, WithForceIndex("idx_status"), WithOptimizerHints("BKA(classes)")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := StudentCURD.Select(&StudentParam{Status: P(1)}, WithForceIndex("idx_status")).
+		UnionAll(StudentCURD.Select(&StudentParam{Status: P(2)})).
+		QueryList(ctx, WithOptimizerHints("MAX_EXECUTION_TIME(1000)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"SELECT /*+ BKA(classes) */ students.id AS `students.id`,classes.name AS `classes.name` " +
+			"FROM students FORCE INDEX (idx_status) INNER JOIN classes ON students.status=classes.id",
+		"SELECT /*+ MAX_EXECUTION_TIME(1000) */ id,name,status FROM students FORCE INDEX (idx_status) WHERE (status=?) " +
+			"UNION ALL SELECT /*+ MAX_EXECUTION_TIME(1000) */ id,name,status FROM students WHERE (status=?)",
+	}
+	stmts := GetRecorder(ctx).Statements()
+	for i, stmt := range stmts {
+		if i < len(want) && stmt.SQL != want[i] {
+			t.Errorf("Statements()[%d] = `%v`, want `%v`", i, stmt.SQL, want[i])
+		}
+	}
+	if len(stmts) != len(want) {
+		t.Errorf("Statements() len = %d, want %d", len(stmts), len(want))
+	}
+
+	_, err = StudentCURD.Select(nil).QueryList(ctx, WithForceIndex("idx) --"))
+	if !errors.Is(err, ErrBadHint) {
+		t.Errorf("Compound.QueryList() err = %v, want %v", err, ErrBadHint)
+	}
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// the aggregate functions
+const (
+	AggCount = "COUNT"
+	AggSum   = "SUM"
+	AggMax   = "MAX"
+	AggMin   = "MIN"
+	AggAvg   = "AVG"
+)
+
+// aggTagName is the tag of the result field of aggregate, like `agg:"sum(score)"`
+const aggTagName = "agg"
+
+// Aggregate is one select item of aggregate query, it's a plain column if Func is empty
+type Aggregate struct {
+	Func   string
+	Column string
+	Alias  string
+}
+
+// GroupItems returns the select items of the result struct type of grouped query:
+// the field tagged like `db:"total" agg:"sum(score)"` is `SUM(score) AS total`, others are plain columns
+func GroupItems(typ reflect.Type) ([]Aggregate, error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the result of grouped query must be struct, got %s", typ)
+	}
+
+	plan := planOf(typ, TagName)
+	items := make([]Aggregate, 0, len(plan.columns))
+	for _, column := range plan.columns {
+		tag := typ.FieldByIndex(plan.byColumn[column]).Tag.Get(aggTagName)
+		if tag == "" {
+			items = append(items, Aggregate{Column: column})
+			continue
+		}
+
+		m := aggregateReg.FindStringSubmatch(strings.TrimSpace(tag))
+		if m == nil {
+			return nil, fmt.Errorf("the agg tag of column %s should be like `sum(column)`, got `%s`", column, tag)
+		}
+		items = append(items, Aggregate{Func: strings.ToUpper(m[1]), Column: m[2], Alias: column})
+	}
+	return items, nil
+}
+
+// aggregate writes the select items, the columns are checked and the aliases are allowed in order by
+func (w *sqlWriter) aggregate(items []Aggregate, groupBy []string) error {
+	if len(items) == 0 {
+		return fmt.Errorf("the select items of aggregate are empty")
+	}
+
+	grouped := make(map[string]bool, len(groupBy))
+	for _, column := range groupBy {
+		grouped[column] = true
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			w.write(",")
+		}
+
+		if item.Func == "" {
+			if !grouped[item.Column] {
+				return fmt.Errorf("the column %s is neither aggregated nor grouped", item.Column)
+			}
+			w.quote(item.Column)
+			continue
+		}
+
+		switch item.Func {
+		case AggCount, AggSum, AggMax, AggMin, AggAvg:
+		default:
+			return fmt.Errorf("unsupported aggregate function: %s", item.Func)
+		}
+		w.write(item.Func, "(")
+		if item.Column == "*" && item.Func == AggCount {
+			w.write("*")
+		} else {
+			if err := w.checkColumn(item.Column); err != nil {
+				return err
+			}
+			w.quote(item.Column)
+		}
+		w.write(")")
+		if item.Alias != "" {
+			w.write(" AS ")
+			w.quote(item.Alias)
+			if w.columns != nil {
+				w.columns[item.Alias] = true
+			}
+		}
+	}
+	return nil
+}
+
+// BuildAggregate builds the query of the aggregate of column, like `SELECT SUM(score) FROM t WHERE ...`,
+// the `_groupby`, `_having`, `_orderby` and `_limit` of where are dropped since the query returns one row
+func (b *Builder) BuildAggregate(table, fn, column string, where any) (sql string, args []any, err error) {
+	wheres := struct2Where(TagName, where)
+	for _, key := range []string{keyGroupBy, keyHaving, keyOrderBy, keyLimit} {
+		delete(wheres, key)
+	}
+	return b.groupQuery(table, []Aggregate{{Func: fn, Column: column}}, nil, wheres)
+}
+
+// BuildGroupQuery builds the query of items grouped by the columns, the `_groupby` of where is replaced by groupBy
+func (b *Builder) BuildGroupQuery(table string, items []Aggregate, groupBy []string, where any) (sql string, args []any, err error) {
+	wheres := struct2Where(TagName, where)
+	if wheres == nil {
+		wheres = map[string]any{}
+	}
+	delete(wheres, keyGroupBy)
+	if len(groupBy) > 0 {
+		wheres[keyGroupBy] = groupBy
+	} else {
+		delete(wheres, keyHaving)
+	}
+	return b.groupQuery(table, items, groupBy, wheres)
+}
+
+func (b *Builder) groupQuery(table string, items []Aggregate, groupBy []string, wheres map[string]any) (sql string, args []any, err error) {
+	if err := b.Hints.check(b.dialect()); err != nil {
+		return "", nil, err
+	}
+
+	w := newSQLWriter(b.dialect())
+	w.allow(b.Columns)
+	w.verb("SELECT", b.Hints)
+	if err := w.aggregate(items, groupBy); err != nil {
+		return "", nil, err
+	}
+	w.write(" FROM ")
+	w.table(table, b.Hints)
+	if err := w.clauses(wheres); err != nil {
+		return "", nil, err
+	}
+	return w.String(), w.args, nil
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type groupResult struct {
+	Status   int     `db:"status"`
+	Total    int64   `db:"total" agg:"count(*)"`
+	ScoreSum float64 `db:"score_sum" agg:"SUM( score )"`
+}
+
+type badGroupResult struct {
+	Total int64 `db:"total" agg:"score+1"`
+}
+
+func TestGroupItems(t *testing.T) {
+	got, err := GroupItems(reflect.TypeOf(&groupResult{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Aggregate{
+		{Column: "status"},
+		{Func: AggCount, Column: "*", Alias: "total"},
+		{Func: AggSum, Column: "score", Alias: "score_sum"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupItems() = %v, want %v", got, want)
+	}
+
+	if _, err := GroupItems(reflect.TypeOf(badGroupResult{})); err == nil {
+		t.Errorf("GroupItems() of bad agg tag should fail")
+	}
+}
+
+func TestBuildGroupQuery(t *testing.T) {
+	items, err := GroupItems(reflect.TypeOf(groupResult{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Builder{Dialect: Postgres, Columns: []string{"id", "status", "score"}}
+
+	runGoldenCases(t, b, []goldenCase{
+		{
+			name: "aggregate",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("students", AggSum, "score", &orderParam{Age: P(1)})
+			},
+			wantSQL:  `SELECT SUM("score") FROM "students" WHERE ("age">$1)`,
+			wantArgs: []any{1},
+		},
+		{
+			name: "count",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("students", AggCount, "*", nil)
+			},
+			wantSQL: `SELECT COUNT(*) FROM "students"`,
+		},
+		{
+			name: "count with paging",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("students", AggCount, "*", &goldenParam{ID: P(int64(1)), OrderBy: P("id desc"), Limit: []uint{20, 10}})
+			},
+			wantSQL:  `SELECT COUNT(*) FROM "students" WHERE ("id"=$1)`,
+			wantArgs: []any{int64(1)},
+		},
+		{
+			name: "aggregate not allowed",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("students", AggMax, "password", nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "aggregate bad function",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("students", "SLEEP", "score", nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "group",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildGroupQuery("students", items, []string{"status"}, &orderParam{
+					Age:     P(1),
+					GroupBy: "ignored",
+					Having:  &orderHaving{Count: P(2)},
+					OrderBy: OrderBy{{Column: "total", Desc: true}},
+				})
+			},
+			wantSQL: `SELECT "status",COUNT(*) AS "total",SUM("score") AS "score_sum" FROM "students" WHERE ("age">$1) ` +
+				`GROUP BY "status" HAVING (count(*)>$2) ORDER BY "total" DESC`,
+			wantArgs: []any{1, 2},
+		},
+		{
+			name: "group column not grouped",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildGroupQuery("students", items, []string{"id"}, nil)
+			},
+			wantErr: true,
+		},
+	})
+}
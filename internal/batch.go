@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"database/sql/driver"
+)
+
+// valueSize estimates the bytes of val sent to database, the other types than string and bytes are counted as 8 bytes
+func valueSize(val any) int {
+	switch v := val.(type) {
+	case Sensitive:
+		return valueSize(v.Value)
+	case Expr:
+		n := len(v.SQL)
+		for _, arg := range v.Args {
+			n += valueSize(arg)
+		}
+		return n
+	case string:
+		return len(v) + 8
+	case []byte:
+		return len(v) + 8
+	case driver.Valuer:
+		if dv, err := v.Value(); err == nil && dv != nil {
+			if _, ok := dv.(driver.Valuer); !ok {
+				return valueSize(dv)
+			}
+		}
+	}
+	return 8
+}
+
+// InsertCost estimates the placeholders and the bytes of one row of insert or update list, they are used to split batches
+func InsertCost(data any) (placeholders, size int) {
+	for column, val := range struct2Assign(TagName, data) {
+		if e, ok := val.(Expr); ok {
+			placeholders += len(e.Args)
+		} else {
+			placeholders++
+		}
+		// the column in sql and the separators
+		size += len(column) + 4 + valueSize(val)
+	}
+	return placeholders, size
+}
+
+// Batches splits n rows into batches of [begin, end), a batch has batchSize rows at most,
+// and its placeholders and size are in the budget unless it has only one row, cost returns the ones of the i-th row
+func Batches(n, batchSize, maxPlaceholders, maxSize int, cost func(i int) (placeholders, size int)) [][2]int {
+	var batches [][2]int
+	begin, placeholders, size := 0, 0, 0
+	for i := 0; i < n; i++ {
+		ph, sz := cost(i)
+		if i > begin && (i-begin >= batchSize || placeholders+ph > maxPlaceholders || size+sz > maxSize) {
+			batches = append(batches, [2]int{begin, i})
+			begin, placeholders, size = i, 0, 0
+		}
+		placeholders += ph
+		size += sz
+	}
+	if begin < n {
+		batches = append(batches, [2]int{begin, n})
+	}
+	return batches
+}
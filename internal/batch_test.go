@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatches(t *testing.T) {
+	costs := [][2]int{{2, 10}, {2, 10}, {2, 10}, {2, 100}, {2, 10}, {2, 10}}
+	cost := func(i int) (int, int) { return costs[i][0], costs[i][1] }
+	tests := []struct {
+		name            string
+		n               int
+		batchSize       int
+		maxPlaceholders int
+		maxSize         int
+		want            [][2]int
+	}{
+		{name: "no limit", n: 6, batchSize: 10, maxPlaceholders: 100, maxSize: 1000, want: [][2]int{{0, 6}}},
+		{name: "batch size", n: 4, batchSize: 2, maxPlaceholders: 100, maxSize: 1000, want: [][2]int{{0, 2}, {2, 4}}},
+		{name: "placeholders", n: 6, batchSize: 10, maxPlaceholders: 5, maxSize: 1000, want: [][2]int{{0, 2}, {2, 4}, {4, 6}}},
+		{name: "size", n: 6, batchSize: 10, maxPlaceholders: 100, maxSize: 50, want: [][2]int{{0, 3}, {3, 4}, {4, 6}}},
+		{name: "empty", n: 0, batchSize: 10, maxPlaceholders: 100, maxSize: 1000, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Batches(tt.n, tt.batchSize, tt.maxPlaceholders, tt.maxSize, cost); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Batches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertCost(t *testing.T) {
+	placeholders, size := InsertCost(&exprParam{
+		ID:    P(int64(1)),
+		Count: &Expr{SQL: ExprColumn + "+?", Args: []any{1}},
+	})
+	if placeholders != 2 {
+		t.Errorf("InsertCost() placeholders = %d, want 2", placeholders)
+	}
+	// id: 2+4+8, count: 5+4+len("{column}+?")+8
+	if want := 14 + 9 + 10 + 8; size != want {
+		t.Errorf("InsertCost() size = %d, want %d", size, want)
+	}
+
+	_, size = InsertCost(&goldenParam{Nation: P("abcdef")})
+	if want := 6 + 4 + 6 + 8; size != want {
+		t.Errorf("InsertCost() size = %d, want %d", size, want)
+	}
+}
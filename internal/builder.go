@@ -1,27 +1,46 @@
 package internal
 
 import (
-	"fmt"
 	"reflect"
 	"strings"
-
-	"github.com/didi/gendry/builder"
 )
 
-func BuildQuery(table string, fields []string, where any) (sql string, args []any, err error) {
-	wheres := struct2Where(TagName, where)
-	return builder.BuildSelect(table, wheres, fields)
+// Builder builds sql of the Dialect, MySQL is used if Dialect is nil
+type Builder struct {
+	Dialect Dialect
+
+	// ConflictColumns is the conflict target of upsert for the dialect which needs it
+	ConflictColumns []string
+
+	// Columns are the columns allowed in order by, group by and having, nil means all identifiers are allowed
+	Columns []string
+
+	// Hints are written into select, update and delete
+	Hints *Hints
+}
+
+func (b *Builder) dialect() Dialect {
+	if b == nil || b.Dialect == nil {
+		return MySQL
+	}
+	return b.Dialect
+}
+
+func (b *Builder) BuildQuery(table string, fields []string, where any) (sql string, args []any, err error) {
+	return buildSelect(b.dialect(), b.Hints, table, selectFields(fields), struct2Where(TagName, where), b.Columns)
 }
 
-func BuildDelete(table string, where any) (sql string, args []any, err error) {
-	wheres := struct2Where(TagName, where)
-	return builder.BuildDelete(table, wheres)
+func (b *Builder) BuildDelete(table string, where any) (sql string, args []any, err error) {
+	return buildDelete(b.dialect(), b.Hints, table, struct2Where(TagName, where))
 }
 
-func BuildUpdate(table string, where, assign any) (sql string, args []any, err error) {
-	wheres := struct2Where(TagName, where)
-	assigns := struct2Assign(TagName, assign)
-	return builder.BuildUpdate(table, wheres, assigns)
+func (b *Builder) BuildUpdate(table string, where, assign any) (sql string, args []any, err error) {
+	return buildUpdate(b.dialect(), b.Hints, table, struct2Where(TagName, where), struct2Assign(TagName, assign))
+}
+
+// BuildUpdateList builds the update of datas with different values per row, the rows are identified by key column
+func (b *Builder) BuildUpdateList(table, key string, datas ...any) (sql string, args []any, err error) {
+	return buildUpdateList(b.dialect(), b.Hints, table, key, struct2AssignList(TagName, datas...))
 }
 
 const (
@@ -30,36 +49,63 @@ const (
 	ReplaceInsert = 2
 )
 
-func BuildInsert(table string, typ int, datas ...any) (sql string, args []any, err error) {
-	assigns := struct2AssignList(TagName, datas...)
-	if typ == CommonInsert {
-		return builder.BuildInsert(table, assigns)
-	}
-	if typ == IgnoreInsert {
-		return builder.BuildInsertIgnore(table, assigns)
-	}
-	if typ == ReplaceInsert {
-		return builder.BuildReplaceInsert(table, assigns)
+func (b *Builder) BuildInsert(table string, typ int, datas ...any) (sql string, args []any, err error) {
+	return buildInsert(b.dialect(), table, typ, b.ConflictColumns, struct2AssignList(TagName, datas...))
+}
+
+// selectFields returns nil if all fields are selected
+func selectFields(fields []string) []string {
+	if len(fields) == 1 && fields[0] == "*" {
+		return nil
 	}
+	return fields
+}
 
-	err = fmt.Errorf("bad type: %d", typ)
+func tagSplitter(dbTag string) (key, opt string) {
+	key, opt, _ = tagParse(dbTag)
 	return
 }
 
-func tagSplitter(dbTag string) (key, opt string) {
+const (
+	// tagFlagSensitive marks the column's value should not be output in logs
+	tagFlagSensitive = "sensitive"
+	// tagFlagInline flattens the nested struct field, the key is the prefix of its columns
+	tagFlagInline = "inline"
+)
+
+// tagFlags are the flags of db tag
+type tagFlags struct {
+	sensitive bool
+	inline    bool
+}
+
+// tagParse split db tag like `name,opt,flag` into key, opt and flags
+func tagParse(dbTag string) (key, opt string, flags tagFlags) {
 	if dbTag == "" {
-		return "", "="
+		return "", "=", flags
 	}
 	i := strings.Index(dbTag, ",")
 	if i == -1 {
-		return dbTag, "="
+		return dbTag, "=", flags
 	}
 
-	opt = strings.TrimSpace(dbTag[i+1:])
+	key = strings.TrimSpace(dbTag[:i])
+	for _, item := range strings.Split(dbTag[i+1:], ",") {
+		item = strings.TrimSpace(item)
+		switch item {
+		case "":
+		case tagFlagSensitive:
+			flags.sensitive = true
+		case tagFlagInline:
+			flags.inline = true
+		default:
+			opt = strings.ToLower(strings.Join(strings.Fields(item), " "))
+		}
+	}
 	if opt == "" {
 		opt = "="
 	}
-	return strings.TrimSpace(dbTag[:i]), opt
+	return key, opt, flags
 }
 
 func struct2Where(tagName string, raw interface{}) map[string]interface{} {
@@ -100,42 +146,57 @@ func struct2map(tagName string, raw interface{}, ignoreOpt bool) map[string]inte
 		return nil
 	}
 
-	for i := 0; i < structVal.NumField(); i++ {
-		valField := structVal.Field(i)
-
-		valFieldKind := valField.Kind()
-		if valFieldKind == reflect.Ptr {
+	for _, field := range planOf(structType, tagName).fields {
+		valField, err := structVal.FieldByIndexErr(field.index)
+		if err != nil { // nil embedded pointer
+			continue
+		}
+		if field.ptr {
 			if valField.IsNil() {
 				continue
 			}
-
 			valField = valField.Elem()
 		}
-
-		if valFieldKind == reflect.Slice {
-			if valField.IsZero() {
+		if field.slice && valField.IsZero() {
+			continue
+		}
+		if valField.Kind() == reflect.Interface {
+			if valField.IsNil() {
 				continue
 			}
+			valField = valField.Elem()
 		}
 
-		typeField := structType.Field(i)
-		dbTag := typeField.Tag.Get(tagName)
-		if dbTag == "-" {
+		if field.group != "" {
+			if !ignoreOpt && valField.Kind() == reflect.Struct {
+				rst[field.key] = whereGroup{or: field.group == keyOr, wheres: struct2map(tagName, valField.Interface(), false)}
+			}
 			continue
 		}
-		key, opt := tagSplitter(dbTag)
-		if key == "" {
-			key = typeField.Name
+		if ignoreOpt && field.expr {
+			continue
 		}
-		if ignoreOpt {
-			rst[key] = valField.Interface()
-		} else {
-			if opt == "" || opt == "=" {
-				rst[key] = valField.Interface()
+		val := valField.Interface()
+		e, isExpr := val.(Expr)
+		if isExpr && e.SQL == "" {
+			continue
+		}
+		sq, isSubQuery := val.(SubQuery)
+		if isSubQuery && (sq.Table == "" || ignoreOpt) {
+			continue
+		}
+		if !isSubQuery && (field.sensitive || IsSensitiveColumn(field.key)) {
+			if isExpr {
+				val = sensitiveExpr(e)
 			} else {
-				rst[key+" "+opt] = valField.Interface()
+				val = sensitiveValue(valField, !ignoreOpt)
 			}
 		}
+		if ignoreOpt {
+			rst[field.key] = val
+		} else {
+			rst[field.whereKey] = val
+		}
 	}
 
 	return rst
@@ -0,0 +1,293 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type goldenParam struct {
+	ID       *int64  `db:"id"`
+	IDs      []int64 `db:"id,in"`
+	NotIDs   []int64 `db:"id,not in"`
+	Name     *string `db:"name,!="`
+	NotName  *string `db:"name,<>"`
+	Age      *int    `db:"age,>"`
+	AgeGte   *int    `db:"age,>="`
+	AgeLt    *int    `db:"age,<"`
+	AgeLte   *int    `db:"age,<="`
+	Title    *string `db:"title,like"`
+	NotTitle *string `db:"title,not like"`
+	Score    []int   `db:"score,between"`
+	NotScore []int   `db:"score,not between"`
+	Status   *int    `db:"status"`
+	Nation   *string `db:"nation"`
+	Ignored  *string `db:"-"`
+	NoTag    *string
+	OrderBy  *string `db:"_orderby"`
+	Limit    []uint  `db:"_limit"`
+}
+
+type goldenGroupParam struct {
+	Age     *int           `db:"age,>"`
+	GroupBy *string        `db:"_groupby"`
+	Having  map[string]any `db:"_having"`
+	OrderBy *string        `db:"_orderby"`
+}
+
+type goldenCase struct {
+	name     string
+	build    func(b *Builder) (string, []any, error)
+	wantSQL  string
+	wantArgs []any
+	wantErr  bool
+}
+
+func goldenQuery(fields []string, where any) func(b *Builder) (string, []any, error) {
+	return func(b *Builder) (string, []any, error) {
+		return b.BuildQuery("students", fields, where)
+	}
+}
+
+func goldenInsert(typ int, datas ...any) func(b *Builder) (string, []any, error) {
+	return func(b *Builder) (string, []any, error) {
+		return b.BuildInsert("students", typ, datas...)
+	}
+}
+
+// the sql of mysql is the same as github.com/didi/gendry v1.3.2 generated
+var mysqlGoldenCases = []goldenCase{
+	{
+		name:    "select nil",
+		build:   goldenQuery([]string{"*"}, nil),
+		wantSQL: "SELECT * FROM students",
+	},
+	{
+		name:    "select empty",
+		build:   goldenQuery(nil, &goldenParam{}),
+		wantSQL: "SELECT * FROM students",
+	},
+	{
+		name:     "select fields",
+		build:    goldenQuery([]string{"id", "name"}, &goldenParam{ID: P(int64(1))}),
+		wantSQL:  "SELECT id,name FROM students WHERE (id=?)",
+		wantArgs: []any{int64(1)},
+	},
+	{
+		name: "select all operators",
+		build: goldenQuery([]string{"*"}, &goldenParam{
+			ID:       P(int64(1)),
+			IDs:      []int64{1, 2},
+			NotIDs:   []int64{3},
+			Name:     P("n1"),
+			NotName:  P("n2"),
+			Age:      P(1),
+			AgeGte:   P(2),
+			AgeLt:    P(3),
+			AgeLte:   P(4),
+			Title:    P("%t%"),
+			NotTitle: P("t%"),
+			Score:    []int{60, 100},
+			NotScore: []int{0, 10},
+			Status:   P(1),
+			Nation:   P("cn"),
+			Ignored:  P("ignored"),
+			NoTag:    P("x"),
+		}),
+		wantSQL: "SELECT * FROM students WHERE (NoTag=? AND id=? AND nation=? AND status=? AND " +
+			"id IN (?,?) AND name!=? AND name!=? AND id NOT IN (?) AND age>? AND age>=? AND age<? AND age<=? AND " +
+			"title LIKE ? AND title NOT LIKE ? AND (score BETWEEN ? AND ?) AND (score NOT BETWEEN ? AND ?))",
+		wantArgs: []any{"x", int64(1), "cn", 1, int64(1), int64(2), "n1", "n2", int64(3), 1, 2, 3, 4, "%t%", "t%", 60, 100, 0, 10},
+	},
+	{
+		name: "select order by and limit",
+		build: goldenQuery([]string{"*"}, &goldenParam{
+			Status:  P(1),
+			OrderBy: P("id desc, name asc"),
+			Limit:   []uint{10, 20},
+		}),
+		wantSQL:  "SELECT * FROM students WHERE (status=?) ORDER BY id DESC,name ASC LIMIT ?,?",
+		wantArgs: []any{1, 10, 20},
+	},
+	{
+		name:     "select one limit",
+		build:    goldenQuery([]string{"*"}, &goldenParam{Limit: []uint{5}}),
+		wantSQL:  "SELECT * FROM students LIMIT ?,?",
+		wantArgs: []any{0, 5},
+	},
+	{
+		name: "select group by and having",
+		build: goldenQuery([]string{"status", "count(*)"}, &goldenGroupParam{
+			Age:     P(10),
+			GroupBy: P("status"),
+			Having:  map[string]any{"status >": 1},
+			OrderBy: P("status asc"),
+		}),
+		wantSQL:  "SELECT status,count(*) FROM students WHERE (age>?) GROUP BY status HAVING (status>?) ORDER BY status ASC",
+		wantArgs: []any{10, 1},
+	},
+	{
+		name:    "select bad order by",
+		build:   goldenQuery([]string{"*"}, &goldenParam{OrderBy: P("id")}),
+		wantErr: true,
+	},
+	{
+		name:    "select bad order by direction",
+		build:   goldenQuery([]string{"*"}, &goldenParam{OrderBy: P("id up")}),
+		wantErr: true,
+	},
+	{
+		name:    "select empty in",
+		build:   goldenQuery([]string{"*"}, &goldenParam{IDs: []int64{}}),
+		wantErr: true,
+	},
+	{
+		name:    "select bad limit",
+		build:   goldenQuery([]string{"*"}, &goldenParam{Limit: []uint{}}),
+		wantErr: true,
+	},
+	{
+		name: "update",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildUpdate("students", &goldenParam{IDs: []int64{1, 2}, Age: P(1)}, &goldenParam{Status: P(1), Nation: P("cn")})
+		},
+		wantSQL:  "UPDATE students SET nation=?,status=? WHERE (id IN (?,?) AND age>?)",
+		wantArgs: []any{"cn", 1, int64(1), int64(2), 1},
+	},
+	{
+		name: "update without where",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildUpdate("students", nil, &goldenParam{Status: P(1)})
+		},
+		wantSQL:  "UPDATE students SET status=?",
+		wantArgs: []any{1},
+	},
+	{
+		name: "delete",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildDelete("students", &goldenParam{ID: P(int64(1)), NotIDs: []int64{2, 3}})
+		},
+		wantSQL:  "DELETE FROM students WHERE (id=? AND id NOT IN (?,?))",
+		wantArgs: []any{int64(1), int64(2), int64(3)},
+	},
+	{
+		name: "delete without where",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildDelete("students", nil)
+		},
+		wantSQL: "DELETE FROM students",
+	},
+	{
+		name:     "insert",
+		build:    goldenInsert(CommonInsert, &goldenParam{ID: P(int64(1)), Nation: P("cn")}),
+		wantSQL:  "INSERT INTO students (id,nation) VALUES (?,?)",
+		wantArgs: []any{int64(1), "cn"},
+	},
+	{
+		name: "insert list",
+		build: goldenInsert(CommonInsert,
+			&goldenParam{ID: P(int64(1)), Nation: P("cn"), Status: P(1)},
+			&goldenParam{ID: P(int64(2)), Nation: P("us"), Status: P(2)},
+		),
+		wantSQL:  "INSERT INTO students (id,nation,status) VALUES (?,?,?),(?,?,?)",
+		wantArgs: []any{int64(1), "cn", 1, int64(2), "us", 2},
+	},
+	{
+		name:     "insert ignore",
+		build:    goldenInsert(IgnoreInsert, &goldenParam{ID: P(int64(1))}),
+		wantSQL:  "INSERT IGNORE INTO students (id) VALUES (?)",
+		wantArgs: []any{int64(1)},
+	},
+	{
+		name:     "insert replace",
+		build:    goldenInsert(ReplaceInsert, &goldenParam{ID: P(int64(1))}),
+		wantSQL:  "REPLACE INTO students (id) VALUES (?)",
+		wantArgs: []any{int64(1)},
+	},
+	{
+		name: "insert not match",
+		build: goldenInsert(CommonInsert,
+			&goldenParam{ID: P(int64(1)), Nation: P("cn")},
+			&goldenParam{ID: P(int64(2))},
+		),
+		wantErr: true,
+	},
+	{
+		name:    "insert empty",
+		build:   goldenInsert(CommonInsert),
+		wantErr: true,
+	},
+	{
+		name:    "insert bad type",
+		build:   goldenInsert(3, &goldenParam{ID: P(int64(1))}),
+		wantErr: true,
+	},
+}
+
+var postgresGoldenCases = []goldenCase{
+	{
+		name: "select",
+		build: goldenQuery([]string{"id", "count(*)"}, &goldenParam{
+			IDs:     []int64{1, 2},
+			Title:   P("%t%"),
+			Score:   []int{60, 100},
+			OrderBy: P("id desc"),
+			Limit:   []uint{10, 20},
+		}),
+		wantSQL:  `SELECT "id",count(*) FROM "students" WHERE ("id" IN ($1,$2) AND "title" LIKE $3 AND ("score" BETWEEN $4 AND $5)) ORDER BY "id" DESC LIMIT $6 OFFSET $7`,
+		wantArgs: []any{int64(1), int64(2), "%t%", 60, 100, 20, 10},
+	},
+	{
+		name: "update",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildUpdate("students", &goldenParam{ID: P(int64(1))}, &goldenParam{Status: P(1), Nation: P("cn")})
+		},
+		wantSQL:  `UPDATE "students" SET "nation"=$1,"status"=$2 WHERE ("id"=$3)`,
+		wantArgs: []any{"cn", 1, int64(1)},
+	},
+	{
+		name: "delete",
+		build: func(b *Builder) (string, []any, error) {
+			return b.BuildDelete("students", &goldenParam{ID: P(int64(1))})
+		},
+		wantSQL:  `DELETE FROM "students" WHERE ("id"=$1)`,
+		wantArgs: []any{int64(1)},
+	},
+	{
+		name:     "insert ignore",
+		build:    goldenInsert(IgnoreInsert, &goldenParam{ID: P(int64(1)), Nation: P("cn")}, &goldenParam{ID: P(int64(2)), Nation: P("us")}),
+		wantSQL:  `INSERT INTO "students" ("id","nation") VALUES ($1,$2),($3,$4) ON CONFLICT DO NOTHING`,
+		wantArgs: []any{int64(1), "cn", int64(2), "us"},
+	},
+}
+
+func runGoldenCases(t *testing.T, b *Builder, cases []goldenCase) {
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.build(b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("build sql = \n`%v`, want \n`%v`", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("build args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuilderGoldenMySQL(t *testing.T) {
+	runGoldenCases(t, &Builder{}, mysqlGoldenCases)
+}
+
+func TestBuilderGoldenPostgres(t *testing.T) {
+	runGoldenCases(t, &Builder{Dialect: Postgres}, postgresGoldenCases)
+}
+
+func P[V any](v V) *V {
+	return &v
+}
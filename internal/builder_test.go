@@ -54,6 +54,30 @@ func Test_tagSplitter(t *testing.T) {
 			wantKey: "a",
 			wantOpt: "not in",
 		},
+		{
+			name:    "case8",
+			dbTag:   "a,sensitive",
+			wantKey: "a",
+			wantOpt: "=",
+		},
+		{
+			name:    "case9",
+			dbTag:   "a, in, sensitive",
+			wantKey: "a",
+			wantOpt: "in",
+		},
+		{
+			name:    "case10",
+			dbTag:   "a, IS  NOT NULL",
+			wantKey: "a",
+			wantOpt: "is not null",
+		},
+		{
+			name:    "case11",
+			dbTag:   "addr_,inline",
+			wantKey: "addr_",
+			wantOpt: "=",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -158,3 +182,42 @@ func TestStruct2Wheres(t *testing.T) {
 		})
 	}
 }
+
+type Account struct {
+	ID     *int64   `db:"id"`
+	Phone  *string  `db:"phone,sensitive"`
+	Phones []string `db:"phone,in,sensitive"`
+	Token  *string  `db:"token"`
+}
+
+func TestStruct2WheresSensitive(t *testing.T) {
+	RegisterSensitiveColumns("token")
+	t.Cleanup(func() {
+		UnregisterSensitiveColumns("token")
+		if IsSensitiveColumn("token") {
+			t.Error("IsSensitiveColumn() after UnregisterSensitiveColumns = true, want false")
+		}
+	})
+
+	id, phone, token := int64(1), "110", "t"
+	got := struct2Where("db", &Account{
+		ID:     &id,
+		Phone:  &phone,
+		Phones: []string{"110", "120"},
+		Token:  &token,
+	})
+	want := map[string]interface{}{
+		"id":       int64(1),
+		"phone":    Sensitive{Value: "110"},
+		"phone in": []any{Sensitive{Value: "110"}, Sensitive{Value: "120"}},
+		"token":    Sensitive{Value: "t"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Struct2Wheres() = \n%v, want \n%v", got, want)
+	}
+
+	args := Unwrap([]any{int64(1), Sensitive{Value: "110"}})
+	if want := []any{int64(1), "110"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("Unwrap() = %v, want %v", args, want)
+	}
+}
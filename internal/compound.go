@@ -0,0 +1,127 @@
+package internal
+
+import "fmt"
+
+// the operators combining selects
+const (
+	Union    = "UNION"
+	UnionAll = "UNION ALL"
+)
+
+// Select is one select of Compound
+type Select struct {
+	Table string
+	// Fields are the selected columns, nil means `*`
+	Fields []string
+	Where  any
+	// Columns are the columns of the Data of table, nil means all identifiers are allowed
+	Columns []string
+	// Hints are the index and optimizer hints of this select
+	Hints *Hints
+}
+
+// CTE is the common table expression `name AS (query)` of WITH
+type CTE struct {
+	Name  string
+	Query *Compound
+}
+
+// Compound is the selects combined by UNION or UNION ALL, with the common table expressions
+type Compound struct {
+	With    []CTE
+	Selects []Select
+	// Unions are the operators between Selects, Unions[i] combines Selects[i] and Selects[i+1]
+	Unions []string
+}
+
+// BuildCompound builds the compound query like `WITH a AS (...) SELECT ... UNION ALL SELECT ...`,
+// the hints of every select are its own, the Hints of builder are not used
+func (b *Builder) BuildCompound(c *Compound) (sql string, args []any, err error) {
+	w := newSQLWriter(b.dialect())
+	if err := w.compound(c); err != nil {
+		return "", nil, err
+	}
+	return w.String(), w.args, nil
+}
+
+func (w *sqlWriter) compound(c *Compound) error {
+	if c == nil || len(c.Selects) == 0 {
+		return fmt.Errorf("the compound query is empty")
+	}
+	if len(c.Unions) != len(c.Selects)-1 {
+		return fmt.Errorf("the compound query has %d selects but %d unions", len(c.Selects), len(c.Unions))
+	}
+
+	for i, cte := range c.With {
+		if !nameReg.MatchString(cte.Name) {
+			return fmt.Errorf("bad name of cte: %q", cte.Name)
+		}
+		if i == 0 {
+			w.write("WITH ")
+		} else {
+			w.write(",")
+		}
+		w.quote(cte.Name)
+		w.write(" AS (")
+		if err := w.compound(cte.Query); err != nil {
+			return err
+		}
+		w.write(")")
+	}
+	if len(c.With) > 0 {
+		w.write(" ")
+	}
+
+	for i, sel := range c.Selects {
+		if i > 0 {
+			switch union := c.Unions[i-1]; union {
+			case Union, UnionAll:
+				w.write(" ", union, " ")
+			default:
+				return fmt.Errorf("unsupported union: %s", union)
+			}
+		}
+		if err := w.selectQuery(sel, i, len(c.Selects) > 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectQuery writes the i-th select of compound, its columns are checked by its own columns.
+// The select with order by or limit of union is wrapped as derived table,
+// since the dialects don't agree on the parenthesized select of union
+func (w *sqlWriter) selectQuery(sel Select, i int, union bool) error {
+	saved := w.columns
+	defer func() { w.columns = saved }()
+	w.columns = nil
+	w.allow(sel.Columns)
+
+	wheres := struct2Where(TagName, sel.Where)
+	if wheres == nil {
+		return fmt.Errorf("the where of select from %s must be struct", sel.Table)
+	}
+	_, ordered := wheres[keyOrderBy]
+	_, limited := wheres[keyLimit]
+	derived := union && (ordered || limited)
+	if derived {
+		w.write("SELECT * FROM (")
+	}
+
+	if err := sel.Hints.check(w.d); err != nil {
+		return err
+	}
+	w.verb("SELECT", sel.Hints)
+	w.fields(sel.Fields)
+	w.write(" FROM ")
+	w.table(sel.Table, sel.Hints)
+	if err := w.clauses(wheres); err != nil {
+		return err
+	}
+
+	if derived {
+		w.write(") ")
+		w.quote(fmt.Sprintf("u%d", i+1))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCompound(t *testing.T) {
+	columns := []string{"id", "name", "status", "age"}
+	sel := func(table string, where any) Select {
+		return Select{Table: table, Fields: []string{"id", "name"}, Where: where, Columns: columns}
+	}
+
+	runGoldenCases(t, &Builder{}, []goldenCase{
+		{
+			name: "union all",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{
+					Selects: []Select{sel("students", &goldenParam{Status: P(1)}), sel("students", &goldenParam{Age: P(18)}), sel("teachers", nil)},
+					Unions:  []string{UnionAll, Union},
+				})
+			},
+			wantSQL:  "SELECT id,name FROM students WHERE (status=?) UNION ALL SELECT id,name FROM students WHERE (age>?) UNION SELECT id,name FROM teachers",
+			wantArgs: []any{1, 18},
+		},
+		{
+			name: "union with order by and limit",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{
+					Selects: []Select{sel("students", &goldenParam{OrderBy: P("age desc"), Limit: []uint{3}}), sel("students", &goldenParam{Status: P(1)})},
+					Unions:  []string{UnionAll},
+				})
+			},
+			wantSQL:  "SELECT * FROM (SELECT id,name FROM students ORDER BY age DESC LIMIT ?,?) u1 UNION ALL SELECT id,name FROM students WHERE (status=?)",
+			wantArgs: []any{0, 3, 1},
+		},
+		{
+			name: "with",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{
+					With:    []CTE{{Name: "adults", Query: &Compound{Selects: []Select{sel("students", &goldenParam{Age: P(18)})}}}},
+					Selects: []Select{sel("adults", &goldenParam{Status: P(1), OrderBy: P("id asc")})},
+				})
+			},
+			wantSQL:  "WITH adults AS (SELECT id,name FROM students WHERE (age>?)) SELECT id,name FROM adults WHERE (status=?) ORDER BY id ASC",
+			wantArgs: []any{18, 1},
+		},
+		{
+			name: "column not allowed",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{Selects: []Select{sel("students", &goldenParam{OrderBy: P("score desc")})}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad cte name",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{
+					With:    []CTE{{Name: "a b", Query: &Compound{Selects: []Select{sel("students", nil)}}}},
+					Selects: []Select{sel("a b", nil)},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "unions not match",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{Selects: []Select{sel("students", nil), sel("students", nil)}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad union",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{Selects: []Select{sel("students", nil), sel("students", nil)}, Unions: []string{"EXCEPT"}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{})
+			},
+			wantErr: true,
+		},
+	})
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: Postgres}).BuildCompound(&Compound{
+		With:    []CTE{{Name: "adults", Query: &Compound{Selects: []Select{sel("students", &goldenParam{Age: P(18)})}}}},
+		Selects: []Select{sel("adults", &goldenParam{Status: P(1)}), sel("adults", &goldenParam{Limit: []uint{1, 2}})},
+		Unions:  []string{UnionAll},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `WITH "adults" AS (SELECT "id","name" FROM "students" WHERE ("age">$1)) SELECT "id","name" FROM "adults" WHERE ("status"=$2) UNION ALL SELECT * FROM (SELECT "id","name" FROM "adults" LIMIT $3 OFFSET $4) "u2"`
+	if gotSQL != want {
+		t.Errorf("BuildCompound() sql = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{18, 1, 2, 1}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildCompound() args = %v, want %v", gotArgs, want)
+	}
+}
@@ -0,0 +1,242 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the sql difference between databases
+type Dialect interface {
+	Name() string
+
+	// Quote quotes the identifier like table and column
+	Quote(ident string) string
+	// Placeholder returns the i-th placeholder, i begins with 1
+	Placeholder(i int) string
+	// Limit returns the limit clause with its args
+	Limit(offset, count uint) (clause string, args []any)
+	// InsertClause returns the verb like `INSERT INTO` and the suffix like `ON CONFLICT DO NOTHING` of insert
+	// columns are the inserted columns, conflictColumns is the conflict target of upsert
+	InsertClause(typ int, columns, conflictColumns []string) (verb, suffix string, err error)
+	// SupportLastInsertID reports whether sql.Result.LastInsertId is supported,
+	// if not, `RETURNING` is used to get the inserted id
+	SupportLastInsertID() bool
+	// InsertedIDs returns the ids of the first and last rows inserted by one plain INSERT from its LastInsertId
+	// and the number of its rows, RowsAffected is not used as it counts the replaced and ignored rows differently
+	InsertedIDs(lastInsertID, rows int64) (first, last int64)
+	// MaxPlaceholders returns the max number of placeholders in one sql
+	MaxPlaceholders() int
+	// SupportHints reports whether the index hints and optimizer hints are supported
+	SupportHints() bool
+	// LockClause returns the suffix of select like ` FOR UPDATE` for the locking read
+	LockClause(mode LockMode, wait LockWait) (string, error)
+}
+
+var (
+	MySQL    Dialect = mysqlDialect{}
+	Postgres Dialect = postgresDialect{}
+	SQLite   Dialect = sqliteDialect{}
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+// Quote keeps the identifier as it is, so the sql is the same as before
+func (mysqlDialect) Quote(ident string) string { return ident }
+func (mysqlDialect) Placeholder(i int) string  { return "?" }
+func (mysqlDialect) Limit(offset, count uint) (string, []any) {
+	return " LIMIT ?,?", []any{int(offset), int(count)}
+}
+func (mysqlDialect) InsertClause(typ int, columns, conflictColumns []string) (string, string, error) {
+	switch typ {
+	case CommonInsert:
+		return "INSERT INTO", "", nil
+	case IgnoreInsert:
+		return "INSERT IGNORE INTO", "", nil
+	case ReplaceInsert:
+		return "REPLACE INTO", "", nil
+	}
+
+	return "", "", fmt.Errorf("bad type: %d", typ)
+}
+func (mysqlDialect) SupportLastInsertID() bool { return true }
+
+// InsertedIDs treats LastInsertId as the first id, mysql returns the id of the first row inserted by the sql
+func (mysqlDialect) InsertedIDs(lastInsertID, rows int64) (int64, int64) {
+	if rows <= 0 {
+		return lastInsertID, lastInsertID
+	}
+	return lastInsertID, lastInsertID + rows - 1
+}
+
+func (mysqlDialect) SupportHints() bool   { return true }
+func (mysqlDialect) MaxPlaceholders() int { return 65535 }
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Quote(ident string) string { return quoteIdent(ident, '"') }
+func (postgresDialect) Placeholder(i int) string  { return "$" + strconv.Itoa(i) }
+func (postgresDialect) Limit(offset, count uint) (string, []any) {
+	return " LIMIT ? OFFSET ?", []any{int(count), int(offset)}
+}
+
+// InsertClause uses `ON CONFLICT` for ignore and replace insert, replace insert requires conflictColumns
+func (d postgresDialect) InsertClause(typ int, columns, conflictColumns []string) (string, string, error) {
+	switch typ {
+	case CommonInsert:
+		return "INSERT INTO", "", nil
+	case IgnoreInsert:
+		return "INSERT INTO", " ON CONFLICT DO NOTHING", nil
+	case ReplaceInsert:
+		suffix, err := upsertSuffix(d, columns, conflictColumns)
+		return "INSERT INTO", suffix, err
+	}
+
+	return "", "", fmt.Errorf("bad type: %d", typ)
+}
+func (postgresDialect) SupportLastInsertID() bool { return false }
+
+// InsertedIDs is not used as the ids are got by `RETURNING`
+func (postgresDialect) InsertedIDs(lastInsertID, rows int64) (int64, int64) {
+	return lastInsertID, lastInsertID
+}
+
+func (postgresDialect) SupportHints() bool   { return false }
+func (postgresDialect) MaxPlaceholders() int { return 65535 }
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Quote(ident string) string { return quoteIdent(ident, '"') }
+func (sqliteDialect) Placeholder(i int) string  { return "?" }
+func (sqliteDialect) Limit(offset, count uint) (string, []any) {
+	return " LIMIT ? OFFSET ?", []any{int(count), int(offset)}
+}
+
+// InsertClause uses `INSERT OR REPLACE` for replace insert if conflictColumns is empty, or upsert by `ON CONFLICT`
+func (d sqliteDialect) InsertClause(typ int, columns, conflictColumns []string) (string, string, error) {
+	switch typ {
+	case CommonInsert:
+		return "INSERT INTO", "", nil
+	case IgnoreInsert:
+		return "INSERT OR IGNORE INTO", "", nil
+	case ReplaceInsert:
+		if len(conflictColumns) == 0 {
+			return "INSERT OR REPLACE INTO", "", nil
+		}
+		suffix, err := upsertSuffix(d, columns, conflictColumns)
+		return "INSERT INTO", suffix, err
+	}
+
+	return "", "", fmt.Errorf("bad type: %d", typ)
+}
+func (sqliteDialect) SupportLastInsertID() bool { return true }
+
+// InsertedIDs treats LastInsertId as the last id, sqlite returns the rowid of the last inserted row
+func (sqliteDialect) InsertedIDs(lastInsertID, rows int64) (int64, int64) {
+	if rows <= 0 {
+		return lastInsertID, lastInsertID
+	}
+	return lastInsertID - rows + 1, lastInsertID
+}
+
+func (sqliteDialect) SupportHints() bool { return false }
+
+// MaxPlaceholders is the default SQLITE_MAX_VARIABLE_NUMBER since 3.32.0
+func (sqliteDialect) MaxPlaceholders() int { return 32766 }
+
+// upsertSuffix returns `ON CONFLICT (k) DO UPDATE SET c=EXCLUDED.c` which updates the columns not in conflict target
+func upsertSuffix(d Dialect, columns, conflictColumns []string) (string, error) {
+	if len(conflictColumns) == 0 {
+		return "", fmt.Errorf("%s replace insert need conflict columns", d.Name())
+	}
+
+	conflicts := make(map[string]bool, len(conflictColumns))
+	targets := make([]string, 0, len(conflictColumns))
+	for _, column := range conflictColumns {
+		conflicts[column] = true
+		targets = append(targets, d.Quote(column))
+	}
+
+	sets := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if conflicts[column] {
+			continue
+		}
+		column = d.Quote(column)
+		sets = append(sets, column+"=EXCLUDED."+column)
+	}
+	if len(sets) == 0 {
+		return " ON CONFLICT (" + strings.Join(targets, ",") + ") DO NOTHING", nil
+	}
+
+	return " ON CONFLICT (" + strings.Join(targets, ",") + ") DO UPDATE SET " + strings.Join(sets, ","), nil
+}
+
+var identReg = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)*$`)
+
+// quoteIdent quotes every part of identifier like `schema.table`
+// the expression like `COUNT(*)` or `*` is kept as it is
+func quoteIdent(ident string, quote byte) string {
+	if !identReg.MatchString(ident) {
+		return ident
+	}
+
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = string(quote) + part + string(quote)
+	}
+	return strings.Join(parts, ".")
+}
+
+// rebind replaces `?` out of the quoted part with the placeholder of dialect
+func rebind(d Dialect, query string) string {
+	if d.Placeholder(1) == "?" {
+		return query
+	}
+
+	buf := strings.Builder{}
+	buf.Grow(len(query) + 16)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'', '"', '`':
+			end := SkipQuoted(query, i)
+			buf.WriteString(query[i:end])
+			i = end - 1
+		case '?':
+			n++
+			buf.WriteString(d.Placeholder(n))
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// SkipQuoted returns the index after the quoted string or identifier begin at i,
+// the quote is escaped by backslash or doubling, except backslash in the backtick
+func SkipQuoted(query string, i int) int {
+	quote := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			if j+1 < len(query) && query[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(query)
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Student struct {
+	ID     *int64  `db:"id"`
+	IDs    []int64 `db:"id,in"`
+	Name   *string `db:"name"`
+	Status *int    `db:"status"`
+
+	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
+}
+
+func TestBuilderPostgres(t *testing.T) {
+	id, name, status, orderBy := int64(1), "n1", 2, "id desc"
+	b := &Builder{Dialect: Postgres}
+
+	tests := []struct {
+		name     string
+		build    func() (string, []any, error)
+		wantSQL  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{
+			name: "query",
+			build: func() (string, []any, error) {
+				return b.BuildQuery("students", []string{"*"}, &Student{
+					IDs:     []int64{1, 2},
+					Name:    &name,
+					OrderBy: &orderBy,
+					Limit:   []uint{10, 20},
+				})
+			},
+			wantSQL:  `SELECT * FROM "students" WHERE ("name"=$1 AND "id" IN ($2,$3)) ORDER BY "id" DESC LIMIT $4 OFFSET $5`,
+			wantArgs: []any{name, int64(1), int64(2), 20, 10},
+		},
+		{
+			name: "query fields",
+			build: func() (string, []any, error) {
+				return b.BuildQuery("public.students", []string{"id", "COUNT(*)"}, &Student{Limit: []uint{5}})
+			},
+			wantSQL:  `SELECT "id",COUNT(*) FROM "public"."students" LIMIT $1 OFFSET $2`,
+			wantArgs: []any{5, 0},
+		},
+		{
+			name: "bad limit",
+			build: func() (string, []any, error) {
+				return b.BuildQuery("students", nil, &Student{Limit: []uint{1, 2, 3}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "update",
+			build: func() (string, []any, error) {
+				return b.BuildUpdate("students", &Student{ID: &id}, &Student{Name: &name, Status: &status})
+			},
+			wantSQL:  `UPDATE "students" SET "name"=$1,"status"=$2 WHERE ("id"=$3)`,
+			wantArgs: []any{name, status, id},
+		},
+		{
+			name: "delete",
+			build: func() (string, []any, error) {
+				return b.BuildDelete("students", &Student{ID: &id})
+			},
+			wantSQL:  `DELETE FROM "students" WHERE ("id"=$1)`,
+			wantArgs: []any{id},
+		},
+		{
+			name: "insert",
+			build: func() (string, []any, error) {
+				return b.BuildInsert("students", CommonInsert, &Student{ID: &id, Name: &name}, &Student{ID: &id, Name: &name})
+			},
+			wantSQL:  `INSERT INTO "students" ("id","name") VALUES ($1,$2),($3,$4)`,
+			wantArgs: []any{id, name, id, name},
+		},
+		{
+			name: "insert ignore",
+			build: func() (string, []any, error) {
+				return b.BuildInsert("students", IgnoreInsert, &Student{ID: &id, Name: &name})
+			},
+			wantSQL:  `INSERT INTO "students" ("id","name") VALUES ($1,$2) ON CONFLICT DO NOTHING`,
+			wantArgs: []any{id, name},
+		},
+		{
+			name: "upsert",
+			build: func() (string, []any, error) {
+				b := &Builder{Dialect: Postgres, ConflictColumns: []string{"id"}}
+				return b.BuildInsert("students", ReplaceInsert, &Student{ID: &id, Name: &name, Status: &status})
+			},
+			wantSQL:  `INSERT INTO "students" ("id","name","status") VALUES ($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","status"=EXCLUDED."status"`,
+			wantArgs: []any{id, name, status},
+		},
+		{
+			name: "upsert without conflict columns",
+			build: func() (string, []any, error) {
+				return b.BuildInsert("students", ReplaceInsert, &Student{ID: &id})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("build err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("build sql = `%v`, want `%v`", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("build args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "case1",
+			query: "SELECT * FROM t WHERE a=? AND b IN (?,?)",
+			want:  "SELECT * FROM t WHERE a=$1 AND b IN ($2,$3)",
+		},
+		{
+			name:  "case2",
+			query: `SELECT '?', "?", 'a\'?', 'b''?' FROM t WHERE a=?`,
+			want:  `SELECT '?', "?", 'a\'?', 'b''?' FROM t WHERE a=$1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(Postgres, tt.query); got != tt.want {
+				t.Errorf("rebind() = `%v`, want `%v`", got, tt.want)
+			}
+			if got := rebind(MySQL, tt.query); got != tt.query {
+				t.Errorf("rebind() of mysql = `%v`, want `%v`", got, tt.query)
+			}
+		})
+	}
+}
+
+func TestBuilderSQLite(t *testing.T) {
+	id, name := int64(1), "n1"
+	tests := []struct {
+		name    string
+		builder *Builder
+		typ     int
+		wantSQL string
+	}{
+		{
+			name:    "insert ignore",
+			builder: &Builder{Dialect: SQLite},
+			typ:     IgnoreInsert,
+			wantSQL: `INSERT OR IGNORE INTO "students" ("id","name") VALUES (?,?)`,
+		},
+		{
+			name:    "insert replace",
+			builder: &Builder{Dialect: SQLite},
+			typ:     ReplaceInsert,
+			wantSQL: `INSERT OR REPLACE INTO "students" ("id","name") VALUES (?,?)`,
+		},
+		{
+			name:    "upsert",
+			builder: &Builder{Dialect: SQLite, ConflictColumns: []string{"id"}},
+			typ:     ReplaceInsert,
+			wantSQL: `INSERT INTO "students" ("id","name") VALUES (?,?) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, _, err := tt.builder.BuildInsert("students", tt.typ, &Student{ID: &id, Name: &name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("BuildInsert() = `%v`, want `%v`", gotSQL, tt.wantSQL)
+			}
+		})
+	}
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: SQLite}).BuildQuery("students", nil, &Student{Limit: []uint{10, 20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM "students" LIMIT ? OFFSET ?`; gotSQL != want {
+		t.Errorf("BuildQuery() = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{20, 10}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildQuery() args = %v, want %v", gotArgs, want)
+	}
+}
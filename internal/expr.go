@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr is a raw sql expression with its args, it is inlined in sql instead of being bound as one arg
+type Expr struct {
+	SQL  string
+	Args []any
+}
+
+// ExprColumn in Expr.SQL is replaced by the quoted column which the Expr is assigned to or compared with
+const ExprColumn = "{column}"
+
+// keyExpr is the key prefix of raw condition, like `_expr`, `_expr_attrs`
+const keyExpr = "_expr"
+
+func isExprKey(key string) bool {
+	return key == keyExpr || strings.HasPrefix(key, keyExpr+"_")
+}
+
+// expr writes the Expr, column is used to replace ExprColumn
+func (w *sqlWriter) expr(e Expr, column string) {
+	sql := e.SQL
+	if column != "" {
+		sql = strings.ReplaceAll(sql, ExprColumn, w.d.Quote(column))
+	}
+	w.write(sql)
+	w.args = append(w.args, e.Args...)
+}
+
+// value writes the Expr or the placeholder of val
+func (w *sqlWriter) value(val any, column string) {
+	if e, ok := val.(Expr); ok {
+		w.expr(e, column)
+		return
+	}
+	w.arg(val)
+}
+
+// SelectExpr is the computed column `SQL AS Alias` of select, like `COUNT(*) AS cnt`
+type SelectExpr struct {
+	SQL   string
+	Alias string
+}
+
+// Field returns the select field `SQL AS Alias`, the alias must be one of columns if columns is not nil,
+// or be an identifier if columns is nil
+func (e SelectExpr) Field(columns []string) (string, error) {
+	if strings.TrimSpace(e.SQL) == "" {
+		return "", fmt.Errorf("the sql of select expr %s is empty", e.Alias)
+	}
+	if !nameReg.MatchString(e.Alias) {
+		return "", fmt.Errorf("%w: alias %q", ErrColumnNotAllowed, e.Alias)
+	}
+	if columns != nil && !contains(columns, e.Alias) {
+		return "", fmt.Errorf("%w: alias %s", ErrColumnNotAllowed, e.Alias)
+	}
+	return e.SQL + " AS " + e.Alias, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, item := range ss {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrBadHint = errors.New("bad hint")
+
+// the kinds of index hint
+const (
+	UseIndex    = "USE"
+	ForceIndex  = "FORCE"
+	IgnoreIndex = "IGNORE"
+)
+
+// IndexHint is the index hint like `FORCE INDEX (idx_a,idx_b)` written after the table
+type IndexHint struct {
+	Kind    string
+	Indexes []string
+}
+
+// Hints are the index hints and optimizer hints of select, update and delete
+type Hints struct {
+	Index []IndexHint
+	// Optimizer are the optimizer hints like `MAX_EXECUTION_TIME(1000)`, they are written as `/*+ ... */` after the verb
+	Optimizer []string
+}
+
+func (h *Hints) empty() bool {
+	return h == nil || (len(h.Index) == 0 && len(h.Optimizer) == 0)
+}
+
+var (
+	nameReg = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+	// optimizerHintReg matches the hint like `NO_RANGE_OPTIMIZATION(t1 PRIMARY)` or `SET_VAR(sort_buffer_size=16M)`
+	optimizerHintReg = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\([A-Za-z0-9_$@.,= +-]*\)$`)
+)
+
+// check validates the hints, and the dialect must support them
+func (h *Hints) check(d Dialect) error {
+	if h.empty() {
+		return nil
+	}
+	if !d.SupportHints() {
+		return fmt.Errorf("%w: %s doesn't support hints", ErrBadHint, d.Name())
+	}
+	for _, hint := range h.Index {
+		switch hint.Kind {
+		case UseIndex, ForceIndex, IgnoreIndex:
+		default:
+			return fmt.Errorf("%w: index hint kind %q", ErrBadHint, hint.Kind)
+		}
+		if len(hint.Indexes) == 0 && hint.Kind != UseIndex {
+			return fmt.Errorf("%w: %s INDEX without index", ErrBadHint, hint.Kind)
+		}
+		for _, index := range hint.Indexes {
+			if !nameReg.MatchString(index) {
+				return fmt.Errorf("%w: index %q", ErrBadHint, index)
+			}
+		}
+	}
+	for _, hint := range h.Optimizer {
+		if !optimizerHintReg.MatchString(strings.TrimSpace(hint)) {
+			return fmt.Errorf("%w: optimizer hint %q", ErrBadHint, hint)
+		}
+	}
+	return nil
+}
+
+// verb writes the verb like `SELECT ` with the optimizer hints
+func (w *sqlWriter) verb(verb string, h *Hints) {
+	w.write(verb, " ")
+	if h == nil || len(h.Optimizer) == 0 {
+		return
+	}
+	w.write("/*+ ")
+	for i, hint := range h.Optimizer {
+		if i > 0 {
+			w.write(" ")
+		}
+		w.write(strings.TrimSpace(hint))
+	}
+	w.write(" */ ")
+}
+
+// table writes the table with the index hints
+func (w *sqlWriter) table(table string, h *Hints) {
+	w.quote(table)
+	if h == nil {
+		return
+	}
+	for _, hint := range h.Index {
+		w.write(" ", hint.Kind, " INDEX (", strings.Join(hint.Indexes, ","), ")")
+	}
+}
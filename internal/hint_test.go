@@ -0,0 +1,103 @@
+package internal
+
+import "testing"
+
+func TestBuildHints(t *testing.T) {
+	hints := &Hints{
+		Index:     []IndexHint{{Kind: ForceIndex, Indexes: []string{"idx_status", "PRIMARY"}}, {Kind: IgnoreIndex, Indexes: []string{"idx_name"}}},
+		Optimizer: []string{"MAX_EXECUTION_TIME(1000)", " SET_VAR(sort_buffer_size = 16M) "},
+	}
+	runGoldenCases(t, &Builder{Hints: hints}, []goldenCase{
+		{
+			name: "select",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("t", []string{"id"}, &goldenParam{ID: P(int64(1))})
+			},
+			wantSQL:  "SELECT /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16M) */ id FROM t FORCE INDEX (idx_status,PRIMARY) IGNORE INDEX (idx_name) WHERE (id=?)",
+			wantArgs: []any{int64(1)},
+		},
+		{
+			name: "update",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdate("t", &goldenParam{ID: P(int64(1))}, &goldenParam{Status: P(2)})
+			},
+			wantSQL:  "UPDATE /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16M) */ t FORCE INDEX (idx_status,PRIMARY) IGNORE INDEX (idx_name) SET status=? WHERE (id=?)",
+			wantArgs: []any{2, int64(1)},
+		},
+		{
+			name: "count",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildAggregate("t", AggCount, "*", nil)
+			},
+			wantSQL: "SELECT /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16M) */ COUNT(*) FROM t FORCE INDEX (idx_status,PRIMARY) IGNORE INDEX (idx_name)",
+		},
+		{
+			name: "join",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{
+					{Table: "t"},
+					{Table: "u", Kind: InnerJoin, On: []JoinOn{{Left: "t.uid", Right: "u.id"}}},
+				}, []string{"t.id"})
+			},
+			wantSQL: "SELECT /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size = 16M) */ t.id AS `t.id` FROM t FORCE INDEX (idx_status,PRIMARY) IGNORE INDEX (idx_name) INNER JOIN u ON t.uid=u.id",
+		},
+		{
+			name: "compound uses the hints of selects",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildCompound(&Compound{
+					Selects: []Select{
+						{Table: "t", Fields: []string{"id"}, Where: &goldenParam{}, Hints: &Hints{Index: []IndexHint{{Kind: UseIndex, Indexes: []string{"idx_a"}}}}},
+						{Table: "u", Fields: []string{"id"}, Where: &goldenParam{}},
+					},
+					Unions: []string{UnionAll},
+				})
+			},
+			wantSQL: "SELECT id FROM t USE INDEX (idx_a) UNION ALL SELECT id FROM u",
+		},
+		{
+			name: "delete with index hint",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildDelete("t", &goldenParam{ID: P(int64(1))})
+			},
+			wantErr: true,
+		},
+	})
+
+	runGoldenCases(t, &Builder{Hints: &Hints{Optimizer: []string{"BKA(t)"}}}, []goldenCase{
+		{
+			name: "delete",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildDelete("t", &goldenParam{ID: P(int64(1))})
+			},
+			wantSQL:  "DELETE /*+ BKA(t) */ FROM t WHERE (id=?)",
+			wantArgs: []any{int64(1)},
+		},
+	})
+	runGoldenCases(t, &Builder{Hints: &Hints{Index: []IndexHint{{Kind: UseIndex}}}}, []goldenCase{
+		{
+			name:    "empty use index",
+			build:   goldenQuery(nil, nil),
+			wantSQL: "SELECT * FROM students USE INDEX ()",
+		},
+	})
+
+	bads := []*Hints{
+		{Index: []IndexHint{{Kind: "SKIP", Indexes: []string{"idx"}}}},
+		{Index: []IndexHint{{Kind: ForceIndex}}},
+		{Index: []IndexHint{{Kind: ForceIndex, Indexes: []string{"idx) WHERE 1=1 --"}}}},
+		{Optimizer: []string{"BKA(t) */ DROP TABLE t; /*"}},
+		{Optimizer: []string{"MAX_EXECUTION_TIME"}},
+	}
+	for _, hints := range bads {
+		if _, _, err := (&Builder{Hints: hints}).BuildQuery("t", nil, nil); err == nil {
+			t.Errorf("BuildQuery() with hints %+v err = nil, want error", hints)
+		}
+	}
+	if _, _, err := (&Builder{Dialect: Postgres, Hints: &Hints{Optimizer: []string{"SeqScan(t)"}}}).BuildQuery("t", nil, nil); err == nil {
+		t.Error("BuildQuery() of postgres with hints err = nil, want error")
+	}
+	compound := &Compound{Selects: []Select{{Table: "t", Where: &goldenParam{}, Hints: bads[0]}}}
+	if _, _, err := (&Builder{}).BuildCompound(compound); err == nil {
+		t.Error("BuildCompound() with bad hints err = nil, want error")
+	}
+}
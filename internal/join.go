@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the kinds of join
+const (
+	InnerJoin = "INNER JOIN"
+	LeftJoin  = "LEFT JOIN"
+)
+
+// JoinOn is the condition `left=right` of join, the unqualified left column belongs to the first table,
+// and the unqualified right column belongs to the joined table
+type JoinOn struct {
+	Left  string
+	Right string
+}
+
+// JoinTable is one table of join query
+type JoinTable struct {
+	Table string
+	// Columns are the columns of the Data of table, nil means all identifiers are allowed
+	Columns []string
+	// Where is the Param of table, only the special keys like `_orderby` of the first table work,
+	// the conditions of LeftJoin table are written in its ON so that the unmatched rows are kept
+	Where any
+
+	// Kind and On are empty for the first table
+	Kind string
+	On   []JoinOn
+}
+
+// joinColumns resolves the qualified columns of the tables
+type joinColumns struct {
+	tables []JoinTable
+}
+
+// split splits the qualified column like `table.column`, table may be `schema.table`
+func (jc joinColumns) split(qualified string) (*JoinTable, string, error) {
+	i := strings.LastIndexByte(qualified, '.')
+	if i == -1 {
+		return nil, "", fmt.Errorf("%w: %s is not qualified by table", ErrColumnNotAllowed, qualified)
+	}
+
+	table, column := qualified[:i], qualified[i+1:]
+	for i := range jc.tables {
+		if jc.tables[i].Table == table {
+			return &jc.tables[i], column, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: table of %s is not joined", ErrColumnNotAllowed, qualified)
+}
+
+// check checks the qualified column is one of its table
+func (jc joinColumns) check(qualified string) error {
+	t, column, err := jc.split(qualified)
+	if err != nil {
+		return err
+	}
+	if !identReg.MatchString(column) {
+		return fmt.Errorf("%w: %s", ErrColumnNotAllowed, qualified)
+	}
+	if t.Columns == nil {
+		return nil
+	}
+	for _, c := range t.Columns {
+		if c == column {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrColumnNotAllowed, qualified)
+}
+
+// qualify returns column qualified by table if it is not qualified
+func qualify(table, column string) string {
+	if strings.Contains(column, ".") {
+		return column
+	}
+	return table + "." + column
+}
+
+// qualifyWheres qualifies the columns of wheres by table, the special keys are removed
+func qualifyWheres(table string, wheres map[string]any) map[string]any {
+	rst := make(map[string]any, len(wheres))
+	for key, val := range wheres {
+		switch {
+		case isSpecialKey(key):
+		case isExprKey(key):
+			rst[key+"_"+table] = val
+		default:
+			if group, ok := val.(whereGroup); ok {
+				rst[key+"_"+table] = whereGroup{or: group.or, wheres: qualifyWheres(table, group.wheres)}
+				continue
+			}
+			column, op := splitKey(key)
+			rst[qualify(table, column)+" "+op] = val
+		}
+	}
+	return rst
+}
+
+// quoteAlias quotes the alias of select item, the alias of mysql is quoted too as it may contain `.`
+func quoteAlias(d Dialect, alias string) string {
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(alias, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(alias, `"`, `""`) + `"`
+}
+
+// BuildJoinQuery builds the query joining tables, fields are the qualified columns like `table.column`,
+// they are selected with themselves as alias so that they can be scanned by tag.
+// The conditions of every table are qualified and joined by AND, those of the left joined table are in its ON,
+// the order by, group by, having and limit of the first table are used, their columns should be qualified.
+// The index hints of builder are of the first table.
+func (b *Builder) BuildJoinQuery(tables []JoinTable, fields []string) (sql string, args []any, err error) {
+	if len(tables) < 2 {
+		return "", nil, fmt.Errorf("join needs two tables at least")
+	}
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("the fields of join are empty")
+	}
+	jc := joinColumns{tables: tables}
+	d := b.dialect()
+	if err := b.Hints.check(d); err != nil {
+		return "", nil, err
+	}
+
+	w := newSQLWriter(d)
+	w.verb("SELECT", b.Hints)
+	allowed := []string{}
+	for i, field := range fields {
+		if err := jc.check(field); err != nil {
+			return "", nil, err
+		}
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(field)
+		w.write(" AS ", quoteAlias(d, field))
+		allowed = append(allowed, field)
+	}
+
+	for _, t := range tables {
+		for _, column := range t.Columns {
+			allowed = append(allowed, t.Table+"."+column)
+		}
+	}
+	w.allow(allowed)
+
+	base := tables[0]
+	w.write(" FROM ")
+	w.table(base.Table, b.Hints)
+	for _, t := range tables[1:] {
+		if t.Kind != InnerJoin && t.Kind != LeftJoin {
+			return "", nil, fmt.Errorf("unsupported join: %s", t.Kind)
+		}
+		if len(t.On) == 0 {
+			return "", nil, fmt.Errorf("the condition of joining %s is empty", t.Table)
+		}
+
+		w.write(" ", t.Kind, " ")
+		w.quote(t.Table)
+		w.write(" ON ")
+		for i, on := range t.On {
+			left, right := qualify(base.Table, on.Left), qualify(t.Table, on.Right)
+			if err := jc.check(left); err != nil {
+				return "", nil, err
+			}
+			if err := jc.check(right); err != nil {
+				return "", nil, err
+			}
+			if i > 0 {
+				w.write(" AND ")
+			}
+			w.quote(left)
+			w.write("=")
+			w.quote(right)
+		}
+
+		// the conditions of left joined table filter the joined rows rather than the result
+		if t.Kind == LeftJoin {
+			onWheres := qualifyWheres(t.Table, struct2Where(TagName, t.Where))
+			if hasCondition(onWheres) {
+				w.write(" AND ")
+				if err := w.where(onWheres); err != nil {
+					return "", nil, err
+				}
+			}
+		}
+	}
+
+	wheres := map[string]any{}
+	for i, t := range tables {
+		if t.Kind == LeftJoin {
+			continue
+		}
+		tableWheres := struct2Where(TagName, t.Where)
+		for key, val := range qualifyWheres(t.Table, tableWheres) {
+			wheres[key] = val
+		}
+		if i == 0 {
+			for key, val := range tableWheres {
+				if isSpecialKey(key) {
+					wheres[key] = val
+				}
+			}
+		}
+	}
+	if err := w.clauses(wheres); err != nil {
+		return "", nil, err
+	}
+	return w.String(), w.args, nil
+}
@@ -0,0 +1,107 @@
+package internal
+
+import "testing"
+
+type joinOrderParam struct {
+	Status  *int         `db:"status"`
+	UserIDs []int64      `db:"user_id,in"`
+	Or      *joinOrderOr `db:"_or"`
+	OrderBy OrderBy      `db:"_orderby"`
+	Limit   []uint       `db:"_limit"`
+}
+
+type joinOrderOr struct {
+	Amount *int `db:"amount,>"`
+	Status *int `db:"status"`
+}
+
+type joinUserParam struct {
+	Name    *string `db:"name,prefix"`
+	OrderBy *string `db:"_orderby"`
+}
+
+func TestBuildJoinQuery(t *testing.T) {
+	orders := JoinTable{
+		Table:   "orders",
+		Columns: []string{"id", "user_id", "amount", "status"},
+		Where: &joinOrderParam{
+			Status:  P(1),
+			UserIDs: []int64{1, 2},
+			Or:      &joinOrderOr{Amount: P(100), Status: P(2)},
+			OrderBy: OrderBy{{Column: "users.name"}, {Column: "orders.id", Desc: true}},
+			Limit:   []uint{0, 10},
+		},
+	}
+	users := JoinTable{
+		Table:   "users",
+		Columns: []string{"id", "name"},
+		Where:   &joinUserParam{Name: P("n"), OrderBy: P("ignored desc")},
+		Kind:    LeftJoin,
+		On:      []JoinOn{{Left: "user_id", Right: "id"}},
+	}
+	fields := []string{"orders.id", "orders.amount", "users.name"}
+
+	runGoldenCases(t, &Builder{}, []goldenCase{
+		{
+			name: "mysql",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{orders, users}, fields)
+			},
+			wantSQL: "SELECT orders.id AS `orders.id`,orders.amount AS `orders.amount`,users.name AS `users.name` " +
+				"FROM orders LEFT JOIN users ON orders.user_id=users.id AND (users.name LIKE ? ESCAPE '!') " +
+				"WHERE (orders.status=? AND orders.user_id IN (?,?) AND (orders.status=? OR orders.amount>?)) " +
+				"ORDER BY users.name ASC,orders.id DESC LIMIT ?,?",
+			wantArgs: []any{"n%", 1, int64(1), int64(2), 2, 100, 0, 10},
+		},
+		{
+			name: "postgres",
+			build: func(b *Builder) (string, []any, error) {
+				b = &Builder{Dialect: Postgres}
+				return b.BuildJoinQuery([]JoinTable{
+					{Table: "orders", Columns: orders.Columns, Where: &joinOrderParam{Status: P(1)}},
+					{Table: "users", Columns: users.Columns, Kind: InnerJoin, On: []JoinOn{{Left: "orders.user_id", Right: "users.id"}}},
+				}, fields)
+			},
+			wantSQL: `SELECT "orders"."id" AS "orders.id","orders"."amount" AS "orders.amount","users"."name" AS "users.name" ` +
+				`FROM "orders" INNER JOIN "users" ON "orders"."user_id"="users"."id" WHERE ("orders"."status"=$1)`,
+			wantArgs: []any{1},
+		},
+		{
+			name: "field not qualified",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{orders, users}, []string{"id"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "field not allowed",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{orders, users}, []string{"users.password"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "table not joined",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{orders, users}, []string{"items.id"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad on",
+			build: func(b *Builder) (string, []any, error) {
+				bad := users
+				bad.On = []JoinOn{{Left: "user_id", Right: "id OR 1=1"}}
+				return b.BuildJoinQuery([]JoinTable{orders, bad}, fields)
+			},
+			wantErr: true,
+		},
+		{
+			name: "one table",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildJoinQuery([]JoinTable{orders}, fields)
+			},
+			wantErr: true,
+		},
+	})
+}
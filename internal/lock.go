@@ -0,0 +1,82 @@
+package internal
+
+import "fmt"
+
+// LockMode is the locking read of select
+type LockMode int
+
+const (
+	LockNone LockMode = iota
+	LockForUpdate
+	LockShare
+)
+
+// LockWait is the behavior of locking read on the rows locked by others
+type LockWait int
+
+const (
+	// LockWaitDefault waits until the rows are unlocked
+	LockWaitDefault LockWait = iota
+	// LockNoWait fails at once
+	LockNoWait
+	// LockSkipLocked skips the locked rows
+	LockSkipLocked
+)
+
+func (wait LockWait) suffix() (string, error) {
+	switch wait {
+	case LockWaitDefault:
+		return "", nil
+	case LockNoWait:
+		return " NOWAIT", nil
+	case LockSkipLocked:
+		return " SKIP LOCKED", nil
+	}
+	return "", fmt.Errorf("bad lock wait: %d", wait)
+}
+
+// LockClause returns the locking clause like ` FOR UPDATE SKIP LOCKED`,
+// share lock without wait is ` LOCK IN SHARE MODE` which works before MySQL 8.0,
+// and ` FOR SHARE` with wait since `LOCK IN SHARE MODE` doesn't support it
+func (mysqlDialect) LockClause(mode LockMode, wait LockWait) (string, error) {
+	suffix, err := wait.suffix()
+	if err != nil {
+		return "", err
+	}
+	switch mode {
+	case LockNone:
+		return "", nil
+	case LockForUpdate:
+		return " FOR UPDATE" + suffix, nil
+	case LockShare:
+		if suffix == "" {
+			return " LOCK IN SHARE MODE", nil
+		}
+		return " FOR SHARE" + suffix, nil
+	}
+	return "", fmt.Errorf("bad lock mode: %d", mode)
+}
+
+func (postgresDialect) LockClause(mode LockMode, wait LockWait) (string, error) {
+	suffix, err := wait.suffix()
+	if err != nil {
+		return "", err
+	}
+	switch mode {
+	case LockNone:
+		return "", nil
+	case LockForUpdate:
+		return " FOR UPDATE" + suffix, nil
+	case LockShare:
+		return " FOR SHARE" + suffix, nil
+	}
+	return "", fmt.Errorf("bad lock mode: %d", mode)
+}
+
+// LockClause fails for any lock, sqlite locks the whole database and has no locking read
+func (d sqliteDialect) LockClause(mode LockMode, wait LockWait) (string, error) {
+	if mode == LockNone {
+		return "", nil
+	}
+	return "", fmt.Errorf("%s doesn't support locking read", d.Name())
+}
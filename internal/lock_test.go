@@ -0,0 +1,36 @@
+package internal
+
+import "testing"
+
+func TestLockClause(t *testing.T) {
+	tests := []struct {
+		d       Dialect
+		mode    LockMode
+		wait    LockWait
+		want    string
+		wantErr bool
+	}{
+		{d: MySQL, mode: LockNone, wait: LockSkipLocked, want: ""},
+		{d: MySQL, mode: LockForUpdate, want: " FOR UPDATE"},
+		{d: MySQL, mode: LockForUpdate, wait: LockSkipLocked, want: " FOR UPDATE SKIP LOCKED"},
+		{d: MySQL, mode: LockShare, want: " LOCK IN SHARE MODE"},
+		{d: MySQL, mode: LockShare, wait: LockNoWait, want: " FOR SHARE NOWAIT"},
+		{d: MySQL, mode: LockForUpdate, wait: LockWait(9), wantErr: true},
+		{d: MySQL, mode: LockMode(9), wantErr: true},
+		{d: Postgres, mode: LockForUpdate, wait: LockNoWait, want: " FOR UPDATE NOWAIT"},
+		{d: Postgres, mode: LockShare, want: " FOR SHARE"},
+		{d: Postgres, mode: LockShare, wait: LockSkipLocked, want: " FOR SHARE SKIP LOCKED"},
+		{d: SQLite, mode: LockNone, want: ""},
+		{d: SQLite, mode: LockForUpdate, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.d.LockClause(tt.mode, tt.wait)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s LockClause(%d, %d) err = %v, wantErr %v", tt.d.Name(), tt.mode, tt.wait, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s LockClause(%d, %d) = `%v`, want `%v`", tt.d.Name(), tt.mode, tt.wait, got, tt.want)
+		}
+	}
+}
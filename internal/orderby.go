@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrColumnNotAllowed = errors.New("column not allowed")
+
+// Order is one key of ORDER BY
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// OrderBy is the typed value of `_orderby`, its columns are validated
+type OrderBy []Order
+
+// aggregateReg matches the aggregate of column like `COUNT(*)`, `sum(score)`
+var aggregateReg = regexp.MustCompile(`(?i)^(count|sum|avg|min|max)\(\s*(\*|[A-Za-z_][A-Za-z0-9_$.]*)\s*\)$`)
+
+// checkColumn checks column is one of the allowed columns,
+// it must be an identifier if the allowed columns are not set
+func (w *sqlWriter) checkColumn(column string) error {
+	if w.columns == nil {
+		if !identReg.MatchString(column) {
+			return fmt.Errorf("%w: %s", ErrColumnNotAllowed, column)
+		}
+		return nil
+	}
+	if !w.columns[column] {
+		return fmt.Errorf("%w: %s", ErrColumnNotAllowed, column)
+	}
+	return nil
+}
+
+// checkAggregate checks column or the column of aggregate like `COUNT(column)`
+func (w *sqlWriter) checkAggregate(expr string) error {
+	if m := aggregateReg.FindStringSubmatch(expr); m != nil {
+		if m[2] == "*" {
+			return nil
+		}
+		return w.checkColumn(m[2])
+	}
+	return w.checkColumn(expr)
+}
+
+// orderBy writes order by clause of value like `a desc, b asc` or OrderBy
+func (w *sqlWriter) orderBy(val any) error {
+	if orders, ok := val.(OrderBy); ok {
+		return w.typedOrderBy(orders)
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return fmt.Errorf(`the value of "%s" must be of string or OrderBy type`, keyOrderBy)
+	}
+
+	for i, item := range strings.Split(s, ",") {
+		fields := strings.Fields(item)
+		if len(fields) != 2 {
+			return fmt.Errorf(`the value of "%s" should be "field direction [,field direction]"`, keyOrderBy)
+		}
+		direction := strings.ToUpper(fields[1])
+		if direction != "ASC" && direction != "DESC" {
+			return fmt.Errorf(`the direction of "%s" should be ASC or DESC`, keyOrderBy)
+		}
+		if err := w.checkColumn(fields[0]); err != nil {
+			return err
+		}
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(fields[0])
+		w.write(" ", direction)
+	}
+	return nil
+}
+
+func (w *sqlWriter) typedOrderBy(orders OrderBy) error {
+	if len(orders) == 0 {
+		return fmt.Errorf(`the value of "%s" is empty`, keyOrderBy)
+	}
+
+	for i, order := range orders {
+		if err := w.checkColumn(order.Column); err != nil {
+			return err
+		}
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(order.Column)
+		if order.Desc {
+			w.write(" DESC")
+		} else {
+			w.write(" ASC")
+		}
+	}
+	return nil
+}
+
+// groupBy writes group by clause of value like `a, b` or []string
+func (w *sqlWriter) groupBy(val any) error {
+	var columns []string
+	switch v := val.(type) {
+	case string:
+		for _, column := range strings.Split(v, ",") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+	case []string:
+		columns = v
+	default:
+		return fmt.Errorf(`the value of "%s" must be of string or []string type`, keyGroupBy)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf(`the value of "%s" is empty`, keyGroupBy)
+	}
+
+	for i, column := range columns {
+		if err := w.checkColumn(column); err != nil {
+			return err
+		}
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(column)
+	}
+	return nil
+}
+
+// having writes having clause, the value is map[string]any or Param struct
+func (w *sqlWriter) having(val any) error {
+	having, ok := val.(map[string]any)
+	if !ok {
+		having = struct2Where(TagName, val)
+		if having == nil {
+			return fmt.Errorf(`the value of "%s" must be of map[string]any or struct type`, keyHaving)
+		}
+	}
+
+	for key := range having {
+		if isSpecialKey(key) {
+			continue
+		}
+		column, _ := splitKey(key)
+		if err := w.checkAggregate(column); err != nil {
+			return err
+		}
+	}
+	if hasCondition(having) {
+		w.write(" HAVING ")
+		return w.where(having)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldPlan is the compiled field of struct, see structPlan
+type fieldPlan struct {
+	// index is the index sequence for reflect.Value.FieldByIndex, it's longer than 1 for the flattened field
+	index []int
+	ptr   bool
+	slice bool
+
+	// key is the column of where and assign, the field name is used if no tag
+	key string
+	// whereKey is `key op`, or key if op is `=`
+	whereKey  string
+	sensitive bool
+	// group is keyOr or keyAnd if the key is a group key
+	group string
+	expr  bool
+}
+
+// structPlan is the compiled fields of struct type, it is computed once by planOf.
+// The anonymous struct fields without tag and the struct fields tagged `db:"prefix,inline"` are flattened,
+// the fields of outer struct shadow the ones of the same column in flattened structs.
+type structPlan struct {
+	// fields are the fields of where and assign, the fields tagged `-` are excluded
+	fields []fieldPlan
+	// columns are the columns of the fields tagged, which are selected and scanned
+	columns []string
+	// byColumn maps column to field index
+	byColumn map[string][]int
+}
+
+type planKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+var planCache sync.Map
+
+// planOf returns the cached plan of struct type by tag, typ must be of struct kind
+func planOf(typ reflect.Type, tagName string) *structPlan {
+	key := planKey{typ: typ, tagName: tagName}
+	if plan, ok := planCache.Load(key); ok {
+		return plan.(*structPlan)
+	}
+
+	plan, _ := planCache.LoadOrStore(key, buildPlan(typ, tagName))
+	return plan.(*structPlan)
+}
+
+// planBuilder collects the fields of struct recursively
+type planBuilder struct {
+	tagName string
+	plan    *structPlan
+	// depth of the collected column and where key, the shallower one wins
+	columnDepth map[string]int
+	keyDepth    map[string]int
+	// visiting prevents the recursive embedding
+	visiting map[reflect.Type]bool
+}
+
+func buildPlan(typ reflect.Type, tagName string) *structPlan {
+	pb := &planBuilder{
+		tagName: tagName,
+		plan: &structPlan{
+			columns:  []string{},
+			byColumn: map[string][]int{},
+		},
+		columnDepth: map[string]int{},
+		keyDepth:    map[string]int{},
+		visiting:    map[reflect.Type]bool{},
+	}
+	pb.collect(typ, nil, "")
+	return pb.plan
+}
+
+func (pb *planBuilder) collect(typ reflect.Type, parent []int, prefix string) {
+	pb.visiting[typ] = true
+	defer delete(pb.visiting, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		index := append(parent[:len(parent):len(parent)], i)
+
+		if nested, inlinePrefix, ok := pb.flatten(field); ok {
+			pb.collect(nested, index, prefix+inlinePrefix)
+			continue
+		}
+
+		if column := columnOf(field, pb.tagName); isColumn(column) {
+			pb.addColumn(prefixed(prefix, column), index)
+		}
+
+		dbTag := field.Tag.Get(pb.tagName)
+		if dbTag == "-" || !field.IsExported() {
+			continue
+		}
+		key, opt, flags := tagParse(dbTag)
+		if key == "" {
+			key = field.Name
+		}
+		key = prefixed(prefix, key)
+
+		fp := fieldPlan{
+			index:     index,
+			ptr:       field.Type.Kind() == reflect.Ptr,
+			slice:     field.Type.Kind() == reflect.Slice,
+			key:       key,
+			whereKey:  key,
+			sensitive: flags.sensitive,
+			expr:      isExprKey(key),
+		}
+		if opt != "" && opt != "=" {
+			fp.whereKey = key + " " + opt
+		}
+		fp.group, _ = groupKind(key)
+		pb.addField(fp)
+	}
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// flatten returns the struct type and the column prefix if the field should be flattened
+func (pb *planBuilder) flatten(field reflect.StructField) (reflect.Type, string, bool) {
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		// the nil pointer can not be allocated when scanning
+		if !field.IsExported() {
+			return nil, "", false
+		}
+	}
+	if typ.Kind() != reflect.Struct || pb.visiting[typ] ||
+		reflect.PtrTo(typ).Implements(scannerType) || typ.Implements(valuerType) ||
+		reflect.PtrTo(typ).Implements(byteUnmarshalerType) {
+		return nil, "", false
+	}
+
+	dbTag := field.Tag.Get(pb.tagName)
+	if dbTag == "-" {
+		return nil, "", false
+	}
+	key, _, flags := tagParse(dbTag)
+	if flags.inline {
+		return typ, key, true
+	}
+	if field.Anonymous && key == "" {
+		return typ, "", true
+	}
+	return nil, "", false
+}
+
+// prefixed adds prefix to the column, the special keys begin with `_` are kept
+func prefixed(prefix, key string) string {
+	if prefix == "" || strings.HasPrefix(key, "_") {
+		return key
+	}
+	return prefix + key
+}
+
+func (pb *planBuilder) addColumn(column string, index []int) {
+	if depth, ok := pb.columnDepth[column]; ok {
+		if depth <= len(index) {
+			return
+		}
+		pb.plan.byColumn[column] = index
+		pb.columnDepth[column] = len(index)
+		return
+	}
+
+	pb.plan.columns = append(pb.plan.columns, column)
+	pb.plan.byColumn[column] = index
+	pb.columnDepth[column] = len(index)
+}
+
+func (pb *planBuilder) addField(fp fieldPlan) {
+	if depth, ok := pb.keyDepth[fp.whereKey]; ok {
+		if depth <= len(fp.index) {
+			return
+		}
+		for i := range pb.plan.fields {
+			if pb.plan.fields[i].whereKey == fp.whereKey {
+				pb.plan.fields[i] = fp
+			}
+		}
+		pb.keyDepth[fp.whereKey] = len(fp.index)
+		return
+	}
+
+	pb.plan.fields = append(pb.plan.fields, fp)
+	pb.keyDepth[fp.whereKey] = len(fp.index)
+}
+
+// columnOf returns the column of struct field by the tag, empty if the field is not mapped
+func columnOf(field reflect.StructField, tagName string) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	tag := field.Tag.Get(tagName)
+	if i := strings.IndexByte(tag, ','); i != -1 {
+		tag = tag[:i]
+	}
+	tag = strings.TrimSpace(tag)
+	if tag == "-" {
+		return ""
+	}
+	return tag
+}
+
+// isColumn reports whether the key is a column of table, the special keys like `_limit`, groups and exprs are not
+func isColumn(key string) bool {
+	if key == "" || isSpecialKey(key) || isExprKey(key) {
+		return false
+	}
+	_, isGroup := groupKind(key)
+	return !isGroup
+}
+
+// Columns returns the columns of struct type in field order, they are cached by type
+func Columns(typ reflect.Type) []string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return planOf(typ, TagName).columns
+}
@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestColumns(t *testing.T) {
+	want := []string{"id", "name", "nick", "score", "is_man", "birthday", "remark"}
+	for i := 0; i < 2; i++ {
+		if got := Columns(reflect.TypeOf(&scanData{})); !reflect.DeepEqual(got, want) {
+			t.Errorf("Columns() = %v, want %v", got, want)
+		}
+	}
+
+	// the special keys, groups and exprs are not columns
+	special := []struct {
+		typ  reflect.Type
+		want []string
+	}{
+		{typ: reflect.TypeOf(goldenParam{}), want: []string{"id", "name", "age", "title", "score", "status", "nation"}},
+		{typ: reflect.TypeOf(groupParam{}), want: []string{"status"}},
+		{typ: reflect.TypeOf(exprParam{}), want: []string{"id", "count", "updated_at", "zero", "bad"}},
+	}
+	for _, tt := range special {
+		if got := Columns(tt.typ); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Columns(%v) = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+
+	if got := Columns(reflect.TypeOf(1)); got != nil {
+		t.Errorf("Columns() of int = %v, want nil", got)
+	}
+}
+
+func TestPlanOf(t *testing.T) {
+	plan := planOf(reflect.TypeOf(groupParam{}), "db")
+	if plan != planOf(reflect.TypeOf(groupParam{}), "db") {
+		t.Errorf("planOf() is not cached")
+	}
+
+	want := []fieldPlan{
+		{index: []int{0}, ptr: true, key: "status", whereKey: "status"},
+		{index: []int{1}, ptr: true, key: "_or", whereKey: "_or", group: keyOr},
+		{index: []int{2}, ptr: true, key: "_or_age", whereKey: "_or_age", group: keyOr},
+	}
+	if !reflect.DeepEqual(plan.fields, want) {
+		t.Errorf("planOf() fields = %+v, want %+v", plan.fields, want)
+	}
+
+	plan = planOf(reflect.TypeOf(Person{}), "db")
+	want = []fieldPlan{
+		{index: []int{0}, key: "id", whereKey: "id"},
+		{index: []int{1}, key: "name", whereKey: "name !="},
+		{index: []int{2}, key: "is_man", whereKey: "is_man"},
+		{index: []int{3}, key: "Nation", whereKey: "Nation"},
+		{index: []int{5}, ptr: true, key: "age", whereKey: "age"},
+		{index: []int{6}, ptr: true, key: "company", whereKey: "company"},
+		{index: []int{7}, slice: true, key: "nums", whereKey: "nums in"},
+	}
+	if !reflect.DeepEqual(plan.fields, want) {
+		t.Errorf("planOf() fields = %+v, want %+v", plan.fields, want)
+	}
+}
+
+var benchParam = &goldenParam{
+	ID:      P(int64(1)),
+	IDs:     []int64{1, 2, 3},
+	Name:    P("n1"),
+	Age:     P(10),
+	Title:   P("t%"),
+	Score:   []int{60, 100},
+	Status:  P(1),
+	OrderBy: P("id desc"),
+	Limit:   []uint{0, 10},
+}
+
+func BenchmarkStructPlan(b *testing.B) {
+	typ := reflect.TypeOf(goldenParam{})
+	b.Run("build", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buildPlan(typ, TagName)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			planOf(typ, TagName)
+		}
+	})
+}
+
+func BenchmarkStruct2Where(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		struct2Where(TagName, benchParam)
+	}
+}
+
+func BenchmarkBuildQuery(b *testing.B) {
+	builder := &Builder{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := builder.BuildQuery("students", nil, benchParam); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	columns := []string{"id", "name", "nick", "score", "is_man", "birthday", "remark"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			b.Fatal(err)
+		}
+		rows := sqlmock.NewRows(columns)
+		for j := 0; j < 100; j++ {
+			rows.AddRow(j, "name", "nick", 1.5, true, nil, "remark")
+		}
+		mock.ExpectQuery("SELECT").WillReturnRows(rows)
+		rs, err := db.Query("SELECT")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		got := []*scanData{}
+		if err := Scan(rs, &got); err != nil {
+			b.Fatal(err)
+		}
+		db.Close()
+	}
+}
+
+type BaseModel struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Address struct {
+	City *string `db:"city"`
+	Zip  *string `db:"zip"`
+}
+
+type embedData struct {
+	*BaseModel
+	Name      string   `db:"name"`
+	UpdatedAt string   `db:"updated_at"`
+	Home      Address  `db:"home_,inline"`
+	Work      *Address `db:"work_,inline"`
+	Other     Address  `db:"other"`
+}
+
+type embedParam struct {
+	BaseParam
+	Name *string  `db:"name"`
+	Home *Address `db:"home_,inline"`
+}
+
+type BaseParam struct {
+	ID  *int64  `db:"id"`
+	IDs []int64 `db:"id,in"`
+	Or  *struct {
+		ID   *int64  `db:"id"`
+		Name *string `db:"name"`
+	} `db:"_or"`
+}
+
+func TestPlanEmbedded(t *testing.T) {
+	want := []string{"id", "created_at", "updated_at", "name", "home_city", "home_zip", "work_city", "work_zip", "other"}
+	if got := Columns(reflect.TypeOf(embedData{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+	if got := planOf(reflect.TypeOf(embedData{}), TagName).byColumn["updated_at"]; !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("the outer field should shadow the embedded one, got %v", got)
+	}
+
+	city := "bj"
+	where := struct2Where(TagName, &embedParam{
+		BaseParam: BaseParam{ID: P(int64(1)), IDs: []int64{2, 3}, Or: &struct {
+			ID   *int64  `db:"id"`
+			Name *string `db:"name"`
+		}{Name: P("n")}},
+		Home: &Address{City: &city},
+	})
+	gotSQL, gotArgs, err := buildSelect(MySQL, nil, "t", nil, where, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM t WHERE (home_city=? AND id=? AND id IN (?,?) AND (name=?))"; gotSQL != want {
+		t.Errorf("buildSelect() = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{"bj", int64(1), int64(2), int64(3), "n"}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("buildSelect() args = %v, want %v", gotArgs, want)
+	}
+
+	if got, want := struct2Assign(TagName, &embedParam{Name: P("n")}), map[string]any{"name": "n"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("struct2Assign() = %v, want %v", got, want)
+	}
+}
+
+func TestScanEmbedded(t *testing.T) {
+	created := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "name", "home_city", "work_zip"}).
+		AddRow(1, created, "u", "n1", "bj", "100000")
+
+	got := []*embedData{}
+	if err := Scan(queryRows(t, rows), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []*embedData{{
+		BaseModel: &BaseModel{ID: 1, CreatedAt: created},
+		Name:      "n1",
+		UpdatedAt: "u",
+		Home:      Address{City: P("bj")},
+		Work:      &Address{Zip: P("100000")},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %+v, want %+v", got[0], want[0])
+	}
+}
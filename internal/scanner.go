@@ -2,12 +2,249 @@ package internal
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
 
-	"github.com/didi/gendry/scanner"
+var (
+	ErrScanTarget = errors.New("[scanner]: target must be a pointer to struct or a pointer to slice of struct")
+	ErrEmptyRows  = errors.New("[scanner]: empty result")
 )
 
+// ByteUnmarshaler is implemented by the field types which unmarshal themselves from the bytes of a column, like JSON.
+// UnmarshalByte is called with a copy of the bytes, and is not called for NULL.
+type ByteUnmarshaler interface {
+	UnmarshalByte(data []byte) error
+}
+
+var byteUnmarshalerType = reflect.TypeOf((*ByteUnmarshaler)(nil)).Elem()
+
+// timeFormat is the format of the time scanned into string
+const timeFormat = "2006-01-02 15:04:05"
+
+// Scan scans rows into target and closes rows, target is a pointer to struct or a pointer to slice of struct or *struct.
+// The columns map to the fields by the tag, the unknown columns are discarded.
 func Scan(rs *sql.Rows, target interface{}) error {
-	err := scanner.Scan(rs, target)
+	err := scan(rs, target)
 	_ = rs.Close()
 	return err
 }
+
+func scan(rs *sql.Rows, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrScanTarget
+	}
+	rv = rv.Elem()
+
+	columns, err := rs.Columns()
+	if err != nil {
+		return err
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if !rs.Next() {
+			if err := rs.Err(); err != nil {
+				return err
+			}
+			return ErrEmptyRows
+		}
+		return scanRow(rs, fieldIndex(rv.Type(), columns), rv)
+	case reflect.Slice:
+		elemType := rv.Type().Elem()
+		isPtr := elemType.Kind() == reflect.Ptr
+		if isPtr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return ErrScanTarget
+		}
+
+		index := fieldIndex(elemType, columns)
+		list := reflect.MakeSlice(rv.Type(), 0, 0)
+		for rs.Next() {
+			elem := reflect.New(elemType)
+			if err := scanRow(rs, index, elem.Elem()); err != nil {
+				return err
+			}
+			if !isPtr {
+				elem = elem.Elem()
+			}
+			list = reflect.Append(list, elem)
+		}
+		if err := rs.Err(); err != nil {
+			return err
+		}
+		rv.Set(list)
+		return nil
+	}
+
+	return ErrScanTarget
+}
+
+// fieldIndex returns the field index of columns, nil if the column does not map to field
+func fieldIndex(typ reflect.Type, columns []string) [][]int {
+	byColumn := planOf(typ, TagName).byColumn
+	rst := make([][]int, len(columns))
+	for i, column := range columns {
+		rst[i] = byColumn[column]
+	}
+	return rst
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates the nil embedded pointers
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(idx)
+	}
+	return rv
+}
+
+// scanRow scans current row into the struct value rv.
+// The fields of bool, string or ByteUnmarshaler are scanned by converter,
+// the fields implemented sql.Scanner or of pointer type are scanned directly,
+// others are scanned by a pointer temp so that NULL keeps the zero value.
+func scanRow(rs *sql.Rows, index [][]int, rv reflect.Value) error {
+	dests := make([]any, len(index))
+	temps := make([]reflect.Value, len(index))
+	fields := make([]reflect.Value, len(index))
+	for i, idx := range index {
+		if idx == nil {
+			dests[i] = new(any)
+			continue
+		}
+
+		field := fieldByIndex(rv, idx)
+		if needConvert(field.Type()) {
+			dests[i] = converter{field: field}
+			continue
+		}
+		if field.Kind() == reflect.Ptr || field.Addr().Type().Implements(scannerType) {
+			dests[i] = field.Addr().Interface()
+			continue
+		}
+
+		fields[i] = field
+		temps[i] = reflect.New(reflect.PtrTo(field.Type()))
+		dests[i] = temps[i].Interface()
+	}
+
+	if err := rs.Scan(dests...); err != nil {
+		return fmt.Errorf("[scanner]: %w", err)
+	}
+
+	for i, temp := range temps {
+		if !temp.IsValid() || temp.Elem().IsNil() {
+			continue
+		}
+		fields[i].Set(temp.Elem().Elem())
+	}
+	return nil
+}
+
+// needConvert reports whether the field of typ is scanned by converter
+func needConvert(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	ptr := reflect.PtrTo(typ)
+	if ptr.Implements(scannerType) {
+		return false
+	}
+	return ptr.Implements(byteUnmarshalerType) || typ.Kind() == reflect.Bool || typ.Kind() == reflect.String
+}
+
+// converter scans a column into field with the conversions which sql.Rows.Scan does not do:
+// bytes to ByteUnmarshaler, number to bool and time to string
+type converter struct {
+	field reflect.Value
+}
+
+func (c converter) Scan(src any) error {
+	if src == nil {
+		return nil
+	}
+	if c.field.Kind() != reflect.Ptr {
+		return convertValue(src, c.field)
+	}
+	ptr := reflect.New(c.field.Type().Elem())
+	if err := convertValue(src, ptr.Elem()); err != nil {
+		return err
+	}
+	c.field.Set(ptr)
+	return nil
+}
+
+func convertValue(src any, field reflect.Value) error {
+	if u, ok := field.Addr().Interface().(ByteUnmarshaler); ok {
+		switch src := src.(type) {
+		case []byte:
+			return u.UnmarshalByte(append([]byte(nil), src...))
+		case string:
+			return u.UnmarshalByte([]byte(src))
+		}
+		return fmt.Errorf("converting %T to %s is unsupported", src, field.Type())
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		switch src := src.(type) {
+		case bool:
+			field.SetBool(src)
+			return nil
+		case int64:
+			field.SetBool(src > 0)
+			return nil
+		case []byte:
+			return convertBool(string(src), field)
+		case string:
+			return convertBool(src, field)
+		}
+	case reflect.String:
+		switch src := src.(type) {
+		case string:
+			field.SetString(src)
+			return nil
+		case []byte:
+			field.SetString(string(src))
+			return nil
+		case int64:
+			field.SetString(strconv.FormatInt(src, 10))
+			return nil
+		case float64:
+			field.SetString(strconv.FormatFloat(src, 'g', -1, 64))
+			return nil
+		case bool:
+			field.SetString(strconv.FormatBool(src))
+			return nil
+		case time.Time:
+			field.SetString(src.Format(timeFormat))
+			return nil
+		}
+	}
+	return fmt.Errorf("converting %T to %s is unsupported", src, field.Type())
+}
+
+// convertBool sets field true if s is a positive number, or parses s by strconv.ParseBool
+func convertBool(s string, field reflect.Value) error {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		field.SetBool(n > 0)
+		return nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("converting %q to %s: %w", s, field.Type(), err)
+	}
+	field.SetBool(b)
+	return nil
+}
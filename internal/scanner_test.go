@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type scanData struct {
+	ID       int64          `db:"id"`
+	Name     string         `db:"name"`
+	Nick     *string        `db:"nick"`
+	Score    float64        `db:"score"`
+	IsMan    bool           `db:"is_man"`
+	Birthday time.Time      `db:"birthday"`
+	Remark   sql.NullString `db:"remark"`
+	Ignored  string         `db:"-"`
+	NoTag    string
+}
+
+func queryRows(t *testing.T, rows *sqlmock.Rows) *sql.Rows {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+	rs, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rs
+}
+
+func TestScan(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	nick := "nick"
+
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		want    []*scanData
+		wantErr bool
+	}{
+		{
+			name: "all columns",
+			rows: sqlmock.NewRows([]string{"id", "name", "nick", "score", "is_man", "birthday", "remark", "NoTag"}).
+				AddRow(int64(1), "n1", nick, 1.5, int64(1), birthday, "r1", "x").
+				AddRow([]byte("2"), []byte("n2"), nil, []byte("2.5"), false, birthday, nil, "x"),
+			want: []*scanData{
+				{ID: 1, Name: "n1", Nick: &nick, Score: 1.5, IsMan: true, Birthday: birthday, Remark: sql.NullString{String: "r1", Valid: true}},
+				{ID: 2, Name: "n2", Score: 2.5, Birthday: birthday},
+			},
+		},
+		{
+			name: "part and unknown columns",
+			rows: sqlmock.NewRows([]string{"name", "unknown"}).
+				AddRow("n1", 1),
+			want: []*scanData{
+				{Name: "n1"},
+			},
+		},
+		{
+			name: "null",
+			rows: sqlmock.NewRows([]string{"id", "name", "score"}).
+				AddRow(nil, nil, nil),
+			want: []*scanData{
+				{},
+			},
+		},
+		{
+			name: "empty",
+			rows: sqlmock.NewRows([]string{"id"}),
+			want: []*scanData{},
+		},
+		{
+			name: "bad type",
+			rows: sqlmock.NewRows([]string{"id"}).
+				AddRow("abc"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []*scanData{}
+			err := Scan(queryRows(t, tt.rows), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanStruct(t *testing.T) {
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "n1").AddRow(2, "n2")
+
+	got := scanData{}
+	if err := Scan(queryRows(t, rows), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (scanData{ID: 1, Name: "n1"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %+v, want %+v", got, want)
+	}
+
+	if err := Scan(queryRows(t, sqlmock.NewRows([]string{"id"})), &got); err == nil {
+		t.Errorf("Scan() empty into struct should fail")
+	}
+}
+
+type scanTags []string
+
+func (t *scanTags) UnmarshalByte(data []byte) error {
+	return json.Unmarshal(data, t)
+}
+
+// scanProfile is a struct column, it is not flattened as it implements ByteUnmarshaler
+type scanProfile struct {
+	Nick string `json:"nick" db:"nick"`
+}
+
+func (p *scanProfile) UnmarshalByte(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+type scanConvertData struct {
+	IsMan    bool        `db:"is_man"`
+	IsAdmin  *bool       `db:"is_admin"`
+	Birthday string      `db:"birthday"`
+	Age      string      `db:"age"`
+	Tags     scanTags    `db:"tags"`
+	Extra    *scanTags   `db:"extra"`
+	Profile  scanProfile `db:"profile"`
+}
+
+func TestScanConvert(t *testing.T) {
+	birthday := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		want    []*scanConvertData
+		wantErr bool
+	}{
+		{
+			name: "convert",
+			rows: sqlmock.NewRows([]string{"is_man", "is_admin", "birthday", "age", "tags", "extra", "profile"}).
+				AddRow(int64(2), []byte("0"), birthday, int64(18), []byte(`["a","b"]`), `["c"]`, []byte(`{"nick":"n"}`)).
+				AddRow([]byte("true"), nil, []byte("2000-01-02"), 1.5, nil, nil, nil),
+			want: []*scanConvertData{
+				{IsMan: true, IsAdmin: new(bool), Birthday: "2000-01-02 03:04:05", Age: "18", Tags: scanTags{"a", "b"}, Extra: &scanTags{"c"}, Profile: scanProfile{Nick: "n"}},
+				{IsMan: true, Birthday: "2000-01-02", Age: "1.5"},
+			},
+		},
+		{
+			name:    "bad bool",
+			rows:    sqlmock.NewRows([]string{"is_man"}).AddRow("abc"),
+			wantErr: true,
+		},
+		{
+			name:    "bad unmarshal",
+			rows:    sqlmock.NewRows([]string{"tags"}).AddRow([]byte("{")),
+			wantErr: true,
+		},
+		{
+			name:    "unmarshal number",
+			rows:    sqlmock.NewRows([]string{"tags"}).AddRow(int64(1)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []*scanConvertData{}
+			err := Scan(queryRows(t, tt.rows), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Sensitive wraps one arg whose value should not be output in logs
+// it must be unwrapped by Unwrap before being sent to database
+type Sensitive struct {
+	Value any
+}
+
+var sensitiveColumns sync.Map
+
+// RegisterSensitiveColumns marks the columns as sensitive for all tables
+func RegisterSensitiveColumns(columns ...string) {
+	for _, column := range columns {
+		sensitiveColumns.Store(column, struct{}{})
+	}
+}
+
+// UnregisterSensitiveColumns removes the columns registered by RegisterSensitiveColumns
+func UnregisterSensitiveColumns(columns ...string) {
+	for _, column := range columns {
+		sensitiveColumns.Delete(column)
+	}
+}
+
+// IsSensitiveColumn reports whether the column is registered as sensitive
+func IsSensitiveColumn(column string) bool {
+	_, ok := sensitiveColumns.Load(column)
+	return ok
+}
+
+// sensitiveValue wraps the field's value
+// a slice will be wrapped element by element if expand, as it will be expanded by IN
+func sensitiveValue(val reflect.Value, expand bool) any {
+	if !expand || val.Kind() != reflect.Slice || val.Type().Elem().Kind() == reflect.Uint8 {
+		return Sensitive{Value: val.Interface()}
+	}
+
+	rst := make([]any, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		rst = append(rst, Sensitive{Value: val.Index(i).Interface()})
+	}
+	return rst
+}
+
+// sensitiveExpr wraps the args of e, its sql is kept as it is written by the code
+func sensitiveExpr(e Expr) Expr {
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		if _, ok := arg.(Sensitive); !ok {
+			arg = Sensitive{Value: arg}
+		}
+		args[i] = arg
+	}
+	return Expr{SQL: e.SQL, Args: args}
+}
+
+// Unwrap returns the args which can be sent to database
+func Unwrap(args []any) []any {
+	i := 0
+	for ; i < len(args); i++ {
+		if _, ok := args[i].(Sensitive); ok {
+			break
+		}
+	}
+	if i == len(args) {
+		return args
+	}
+
+	rst := make([]any, len(args))
+	for i, arg := range args {
+		if s, ok := arg.(Sensitive); ok {
+			arg = s.Value
+		}
+		rst[i] = arg
+	}
+	return rst
+}
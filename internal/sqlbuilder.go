@@ -0,0 +1,680 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnsupportedOperator = errors.New("unsupported operator")
+	ErrInsertEmpty         = errors.New("insert data is empty")
+	ErrInsertNotMatch      = errors.New("insert data not match")
+	ErrUpdateEmpty         = errors.New("update assign is empty")
+	ErrUpdateKeyMissing    = errors.New("update key is missing")
+)
+
+// the special keys of where
+const (
+	keyOrderBy = "_orderby"
+	keyGroupBy = "_groupby"
+	keyHaving  = "_having"
+	keyLimit   = "_limit"
+)
+
+// the key prefixes of nested struct whose conditions are grouped,
+// the conditions of `_or` are joined by OR and the ones of `_and` are joined by AND
+const (
+	keyOr  = "_or"
+	keyAnd = "_and"
+)
+
+// whereGroup is the value of a group key in wheres
+type whereGroup struct {
+	or     bool
+	wheres map[string]any
+}
+
+// groupKind returns keyOr or keyAnd if key is a group key like `_or`, `_or_owner`
+func groupKind(key string) (string, bool) {
+	for _, kind := range []string{keyOr, keyAnd} {
+		if key == kind || strings.HasPrefix(key, kind+"_") {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func isSpecialKey(key string) bool {
+	switch key {
+	case keyOrderBy, keyGroupBy, keyHaving, keyLimit:
+		return true
+	}
+	return false
+}
+
+// the operators of where besides the comparisons
+const (
+	OpIn         = "in"
+	OpNotIn      = "not in"
+	OpLike       = "like"
+	OpNotLike    = "not like"
+	OpPrefix     = "prefix"
+	OpSuffix     = "suffix"
+	OpContains   = "contains"
+	OpBetween    = "between"
+	OpNotBetween = "not between"
+	OpIsNull     = "is null"
+	OpIsNotNull  = "is not null"
+)
+
+// opOrder is the order of conditions in where clause, the conditions of one operator are sorted by column
+var opOrder = []string{"=", OpIn, "!=", "<>", OpNotIn, ">", ">=", "<", "<=", OpLike, OpNotLike, OpBetween, OpNotBetween,
+	OpPrefix, OpSuffix, OpContains, OpIsNull, OpIsNotNull, OpExists, OpNotExists}
+
+// likeEscape is the escape character of prefix, suffix and contains,
+// backslash is not used because it needs to be escaped again in the literal of mysql
+const likeEscape = "!"
+
+var likeReplacer = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+var opIndex = func() map[string]int {
+	rst := make(map[string]int, len(opOrder))
+	for i, op := range opOrder {
+		rst[op] = i
+	}
+	return rst
+}()
+
+// opExpr is the op of raw condition, opGroup is the op of group condition,
+// they are placed after other conditions
+const (
+	opExpr  = "_expr"
+	opGroup = "_group"
+)
+
+func opOrderOf(op string) int {
+	switch op {
+	case opExpr:
+		return len(opOrder)
+	case opGroup:
+		return len(opOrder) + 1
+	}
+	return opIndex[op]
+}
+
+// condition is one `column op value` of where clause, or a group whose column is the key
+type condition struct {
+	column string
+	op     string
+	value  any
+}
+
+// splitKey splits the key of where like `column op`
+func splitKey(key string) (column, op string) {
+	key = strings.TrimSpace(key)
+	i := strings.IndexByte(key, ' ')
+	if i == -1 {
+		return key, "="
+	}
+
+	return key[:i], strings.ToLower(strings.Join(strings.Fields(key[i+1:]), " "))
+}
+
+// resolveConditions returns the conditions of wheres in order, special keys are ignored
+func resolveConditions(wheres map[string]any) ([]condition, error) {
+	conds := make([]condition, 0, len(wheres))
+	for key, val := range wheres {
+		if isSpecialKey(key) {
+			continue
+		}
+		if group, ok := val.(whereGroup); ok {
+			if hasCondition(group.wheres) {
+				conds = append(conds, condition{column: key, op: opGroup, value: group})
+			}
+			continue
+		}
+		if isExprKey(key) {
+			e, ok := val.(Expr)
+			if !ok {
+				return nil, fmt.Errorf(`the value of "%s" must be of Expr type`, key)
+			}
+			conds = append(conds, condition{column: key, op: opExpr, value: e})
+			continue
+		}
+		column, op := splitKey(key)
+		if _, ok := opIndex[op]; !ok {
+			return nil, fmt.Errorf("%w: \"%s\" of column \"%s\"", ErrUnsupportedOperator, op, column)
+		}
+		conds = append(conds, condition{column: column, op: op, value: val})
+	}
+
+	sort.Slice(conds, func(i, j int) bool {
+		if conds[i].op != conds[j].op {
+			return opOrderOf(conds[i].op) < opOrderOf(conds[j].op)
+		}
+		return conds[i].column < conds[j].column
+	})
+	return conds, nil
+}
+
+// sqlWriter writes sql with `?` as placeholder, String rebinds the placeholders for dialect
+type sqlWriter struct {
+	d    Dialect
+	buf  strings.Builder
+	args []any
+
+	// columns are the allowed columns, nil means all identifiers are allowed
+	columns map[string]bool
+}
+
+func newSQLWriter(d Dialect) *sqlWriter {
+	return &sqlWriter{d: d}
+}
+
+func (w *sqlWriter) allow(columns []string) {
+	if columns == nil {
+		return
+	}
+	w.columns = make(map[string]bool, len(columns))
+	for _, column := range columns {
+		w.columns[column] = true
+	}
+}
+
+func (w *sqlWriter) write(ss ...string) {
+	for _, s := range ss {
+		w.buf.WriteString(s)
+	}
+}
+
+func (w *sqlWriter) quote(ident string) {
+	w.buf.WriteString(w.d.Quote(ident))
+}
+
+// arg writes one placeholder of val
+func (w *sqlWriter) arg(val any) {
+	w.buf.WriteByte('?')
+	w.args = append(w.args, val)
+}
+
+func (w *sqlWriter) String() string {
+	return rebind(w.d, w.buf.String())
+}
+
+// where writes where clause like `(a=? AND b IN (?,?))`, nothing is written if no condition
+func (w *sqlWriter) where(wheres map[string]any) error {
+	return w.conditions(wheres, " AND ")
+}
+
+// conditions writes the conditions joined by sep in parentheses
+func (w *sqlWriter) conditions(wheres map[string]any, sep string) error {
+	conds, err := resolveConditions(wheres)
+	if err != nil || len(conds) == 0 {
+		return err
+	}
+
+	w.write("(")
+	for i, cond := range conds {
+		if i > 0 {
+			w.write(sep)
+		}
+		if err := w.condition(cond); err != nil {
+			return err
+		}
+	}
+	w.write(")")
+	return nil
+}
+
+func (w *sqlWriter) condition(cond condition) error {
+	if _, ok := cond.value.(Expr); ok && cond.op != opExpr && !exprOperator(cond.op) {
+		return fmt.Errorf(`the operator of "%s %s" does not support Expr`, cond.column, cond.op)
+	}
+	if _, ok := cond.value.(SubQuery); ok && !subQueryOperator(cond.op) {
+		return fmt.Errorf(`the operator of "%s %s" does not support SubQuery`, cond.column, cond.op)
+	}
+
+	switch cond.op {
+	case opGroup:
+		group := cond.value.(whereGroup)
+		if group.or {
+			return w.conditions(group.wheres, " OR ")
+		}
+		return w.conditions(group.wheres, " AND ")
+	case opExpr:
+		w.write("(")
+		w.expr(cond.value.(Expr), "")
+		w.write(")")
+	case "=", "!=", ">", ">=", "<", "<=":
+		w.quote(cond.column)
+		w.write(cond.op)
+		w.value(cond.value, cond.column)
+	case "<>":
+		w.quote(cond.column)
+		w.write("!=")
+		w.value(cond.value, cond.column)
+	case OpLike, OpNotLike:
+		w.quote(cond.column)
+		w.write(" ", strings.ToUpper(cond.op), " ")
+		w.value(cond.value, cond.column)
+	case OpPrefix, OpSuffix, OpContains:
+		pattern, err := likePattern(cond)
+		if err != nil {
+			return err
+		}
+		w.quote(cond.column)
+		w.write(" LIKE ")
+		w.arg(pattern)
+		w.write(" ESCAPE '", likeEscape, "'")
+	case OpIsNull, OpIsNotNull:
+		val := cond.value
+		if s, ok := val.(Sensitive); ok {
+			val = s.Value
+		}
+		isNull, ok := val.(bool)
+		if !ok {
+			return fmt.Errorf(`the value of "%s %s" must be of bool type`, cond.column, cond.op)
+		}
+		if cond.op == OpIsNotNull {
+			isNull = !isNull
+		}
+		w.quote(cond.column)
+		if isNull {
+			w.write(" IS NULL")
+		} else {
+			w.write(" IS NOT NULL")
+		}
+	case OpExists, OpNotExists:
+		sq, ok := cond.value.(SubQuery)
+		if !ok {
+			return fmt.Errorf(`the value of "%s %s" must be of SubQuery type`, cond.column, cond.op)
+		}
+		w.write(strings.ToUpper(cond.op), " ")
+		return w.subQuery(sq, true)
+	case OpIn, OpNotIn:
+		if sq, ok := cond.value.(SubQuery); ok {
+			w.quote(cond.column)
+			w.write(" ", strings.ToUpper(cond.op), " ")
+			return w.subQuery(sq, false)
+		}
+		vals, err := sliceValues(cond)
+		if err != nil {
+			return err
+		}
+		if len(vals) == 0 {
+			return fmt.Errorf(`the value of "%s %s" must contain at least one element`, cond.column, cond.op)
+		}
+		w.quote(cond.column)
+		w.write(" ", strings.ToUpper(cond.op), " (")
+		for i, val := range vals {
+			if i > 0 {
+				w.write(",")
+			}
+			w.arg(val)
+		}
+		w.write(")")
+	case OpBetween, OpNotBetween:
+		vals, err := sliceValues(cond)
+		if err != nil {
+			return err
+		}
+		if len(vals) != 2 {
+			return fmt.Errorf(`the value of "%s %s" must contain two elements`, cond.column, cond.op)
+		}
+		w.write("(")
+		w.quote(cond.column)
+		w.write(" ", strings.ToUpper(cond.op), " ")
+		w.arg(vals[0])
+		w.write(" AND ")
+		w.arg(vals[1])
+		w.write(")")
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOperator, cond.op)
+	}
+
+	return nil
+}
+
+// exprOperator reports whether the value of op can be an Expr
+func exprOperator(op string) bool {
+	switch op {
+	case "=", "!=", "<>", ">", ">=", "<", "<=", OpLike, OpNotLike:
+		return true
+	}
+	return false
+}
+
+// likePattern escapes the string value and adds the wildcards of the operator
+func likePattern(cond condition) (any, error) {
+	val, sensitive := cond.value, false
+	if s, ok := val.(Sensitive); ok {
+		val, sensitive = s.Value, true
+	}
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf(`the value of "%s %s" must be of string type`, cond.column, cond.op)
+	}
+
+	str = likeReplacer.Replace(str)
+	switch cond.op {
+	case OpPrefix:
+		str = str + "%"
+	case OpSuffix:
+		str = "%" + str
+	default:
+		str = "%" + str + "%"
+	}
+
+	if sensitive {
+		return Sensitive{Value: str}, nil
+	}
+	return str, nil
+}
+
+func sliceValues(cond condition) ([]any, error) {
+	if vals, ok := cond.value.([]any); ok {
+		return vals, nil
+	}
+
+	rv := reflect.ValueOf(cond.value)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf(`the value of "%s %s" must be a slice`, cond.column, cond.op)
+	}
+	vals := make([]any, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		vals = append(vals, rv.Index(i).Interface())
+	}
+	return vals, nil
+}
+
+func (w *sqlWriter) limit(val any) error {
+	arr, ok := val.([]uint)
+	if !ok {
+		return fmt.Errorf(`the value of "%s" must be of []uint type`, keyLimit)
+	}
+
+	var clause string
+	var args []any
+	switch len(arr) {
+	case 1:
+		clause, args = w.d.Limit(0, arr[0])
+	case 2:
+		clause, args = w.d.Limit(arr[0], arr[1])
+	default:
+		return fmt.Errorf(`the value of "%s" must contain one or two uint elements`, keyLimit)
+	}
+
+	w.write(clause)
+	w.args = append(w.args, args...)
+	return nil
+}
+
+func (w *sqlWriter) fields(fields []string) {
+	if len(fields) == 0 {
+		w.write("*")
+		return
+	}
+
+	for i, field := range fields {
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(field)
+	}
+}
+
+// buildSelect builds select sql, the columns of order by, group by and having must be in columns if it is not nil
+func buildSelect(d Dialect, hints *Hints, table string, fields []string, wheres map[string]any, columns []string) (string, []any, error) {
+	if err := hints.check(d); err != nil {
+		return "", nil, err
+	}
+
+	w := newSQLWriter(d)
+	w.allow(columns)
+	w.verb("SELECT", hints)
+	w.fields(fields)
+	w.write(" FROM ")
+	w.table(table, hints)
+	if err := w.clauses(wheres); err != nil {
+		return "", nil, err
+	}
+
+	return w.String(), w.args, nil
+}
+
+// clauses writes the clauses after FROM of select: where, group by, having, order by and limit
+func (w *sqlWriter) clauses(wheres map[string]any) error {
+	if hasCondition(wheres) {
+		w.write(" WHERE ")
+		if err := w.where(wheres); err != nil {
+			return err
+		}
+	}
+
+	if val, ok := wheres[keyGroupBy]; ok {
+		w.write(" GROUP BY ")
+		if err := w.groupBy(val); err != nil {
+			return err
+		}
+
+		if val, ok := wheres[keyHaving]; ok {
+			if err := w.having(val); err != nil {
+				return err
+			}
+		}
+	}
+
+	if val, ok := wheres[keyOrderBy]; ok {
+		w.write(" ORDER BY ")
+		if err := w.orderBy(val); err != nil {
+			return err
+		}
+	}
+
+	if val, ok := wheres[keyLimit]; ok {
+		if err := w.limit(val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasCondition(wheres map[string]any) bool {
+	for key, val := range wheres {
+		if isSpecialKey(key) {
+			continue
+		}
+		if group, ok := val.(whereGroup); ok && !hasCondition(group.wheres) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func buildUpdate(d Dialect, hints *Hints, table string, wheres, assigns map[string]any) (string, []any, error) {
+	if len(assigns) == 0 {
+		return "", nil, ErrUpdateEmpty
+	}
+	if err := hints.check(d); err != nil {
+		return "", nil, err
+	}
+
+	w := newSQLWriter(d)
+	w.verb("UPDATE", hints)
+	w.table(table, hints)
+	w.write(" SET ")
+	for i, column := range sortedKeys(assigns) {
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(column)
+		w.write("=")
+		w.value(assigns[column], column)
+	}
+
+	if hasCondition(wheres) {
+		w.write(" WHERE ")
+		if err := w.where(wheres); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return w.String(), w.args, nil
+}
+
+// buildUpdateList builds the update of different values per row identified by key,
+// like `UPDATE t SET a=CASE id WHEN ? THEN ? ELSE a END WHERE id IN (?)`,
+// the column not assigned by a row keeps its value for the row
+func buildUpdateList(d Dialect, hints *Hints, table, key string, assigns []map[string]any) (string, []any, error) {
+	if len(assigns) == 0 {
+		return "", nil, ErrUpdateEmpty
+	}
+	if err := hints.check(d); err != nil {
+		return "", nil, err
+	}
+
+	keys := make([]any, 0, len(assigns))
+	columnSet := map[string]any{}
+	for _, assign := range assigns {
+		val, ok := assign[key]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", ErrUpdateKeyMissing, key)
+		}
+		if _, ok := val.(Expr); ok {
+			return "", nil, fmt.Errorf("the update key %s can't be Expr", key)
+		}
+		keys = append(keys, val)
+		for column := range assign {
+			if column != key {
+				columnSet[column] = nil
+			}
+		}
+	}
+	if len(columnSet) == 0 {
+		return "", nil, ErrUpdateEmpty
+	}
+
+	w := newSQLWriter(d)
+	w.verb("UPDATE", hints)
+	w.table(table, hints)
+	w.write(" SET ")
+	for i, column := range sortedKeys(columnSet) {
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(column)
+		w.write("=CASE ")
+		w.quote(key)
+		for j, assign := range assigns {
+			val, ok := assign[column]
+			if !ok {
+				continue
+			}
+			w.write(" WHEN ")
+			w.arg(keys[j])
+			w.write(" THEN ")
+			w.value(val, column)
+		}
+		w.write(" ELSE ")
+		w.quote(column)
+		w.write(" END")
+	}
+
+	w.write(" WHERE ")
+	w.quote(key)
+	w.write(" IN (")
+	for i, val := range keys {
+		if i > 0 {
+			w.write(",")
+		}
+		w.arg(val)
+	}
+	w.write(")")
+
+	return w.String(), w.args, nil
+}
+
+// buildDelete builds delete sql, the index hints are not allowed since single-table delete doesn't support them
+func buildDelete(d Dialect, hints *Hints, table string, wheres map[string]any) (string, []any, error) {
+	if err := hints.check(d); err != nil {
+		return "", nil, err
+	}
+	if hints != nil && len(hints.Index) > 0 {
+		return "", nil, fmt.Errorf("%w: delete doesn't support index hints", ErrBadHint)
+	}
+
+	w := newSQLWriter(d)
+	w.verb("DELETE", hints)
+	w.write("FROM ")
+	w.quote(table)
+
+	if hasCondition(wheres) {
+		w.write(" WHERE ")
+		if err := w.where(wheres); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return w.String(), w.args, nil
+}
+
+func buildInsert(d Dialect, table string, typ int, conflictColumns []string, assigns []map[string]any) (string, []any, error) {
+	if len(assigns) == 0 {
+		return "", nil, ErrInsertEmpty
+	}
+
+	columns := sortedKeys(assigns[0])
+	verb, suffix, err := d.InsertClause(typ, columns, conflictColumns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	w := newSQLWriter(d)
+	w.write(verb, " ")
+	w.quote(table)
+	w.write(" (")
+	for i, column := range columns {
+		if i > 0 {
+			w.write(",")
+		}
+		w.quote(column)
+	}
+	w.write(") VALUES ")
+	for i, assign := range assigns {
+		if len(assign) != len(columns) {
+			return "", nil, ErrInsertNotMatch
+		}
+		if i > 0 {
+			w.write(",")
+		}
+		w.write("(")
+		for j, column := range columns {
+			val, ok := assign[column]
+			if !ok {
+				return "", nil, ErrInsertNotMatch
+			}
+			if j > 0 {
+				w.write(",")
+			}
+			w.value(val, column)
+		}
+		w.write(")")
+	}
+	w.write(suffix)
+
+	return w.String(), w.args, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,405 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type operatorParam struct {
+	Name       *string  `db:"name,prefix"`
+	Title      *string  `db:"title,suffix"`
+	Remark     *string  `db:"remark,contains"`
+	Phone      *string  `db:"phone,contains,sensitive"`
+	Nick       *string  `db:"nick,Not  Like"`
+	Age        []int    `db:"age,between"`
+	Score      *float64 `db:"score,>="`
+	Status     *int     `db:"status,<>"`
+	DeletedAt  *bool    `db:"deleted_at,is null"`
+	UpdatedAt  *bool    `db:"updated_at,is not null"`
+	BadOpt     *int     `db:"bad,~"`
+	BadBetween []int    `db:"bad,between"`
+	BadPrefix  *int     `db:"bad,prefix"`
+	BadIsNull  *int     `db:"bad,is null"`
+}
+
+func TestBuildOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		where    *operatorParam
+		wantSQL  string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:     "like",
+			where:    &operatorParam{Name: P("a%b_c!"), Title: P("x"), Remark: P("y"), Nick: P("n%")},
+			wantSQL:  "SELECT * FROM students WHERE (nick NOT LIKE ? AND name LIKE ? ESCAPE '!' AND title LIKE ? ESCAPE '!' AND remark LIKE ? ESCAPE '!')",
+			wantArgs: []any{"n%", "a!%b!_c!!%", "%x", "%y%"},
+		},
+		{
+			name:     "sensitive contains",
+			where:    &operatorParam{Phone: P("110")},
+			wantSQL:  "SELECT * FROM students WHERE (phone LIKE ? ESCAPE '!')",
+			wantArgs: []any{Sensitive{Value: "%110%"}},
+		},
+		{
+			name:     "comparisons and between",
+			where:    &operatorParam{Age: []int{1, 2}, Score: P(1.5), Status: P(1)},
+			wantSQL:  "SELECT * FROM students WHERE (status!=? AND score>=? AND (age BETWEEN ? AND ?))",
+			wantArgs: []any{1, 1.5, 1, 2},
+		},
+		{
+			name:    "is null",
+			where:   &operatorParam{DeletedAt: P(true), UpdatedAt: P(true)},
+			wantSQL: "SELECT * FROM students WHERE (deleted_at IS NULL AND updated_at IS NOT NULL)",
+		},
+		{
+			name:    "is not null",
+			where:   &operatorParam{DeletedAt: P(false), UpdatedAt: P(false)},
+			wantSQL: "SELECT * FROM students WHERE (deleted_at IS NOT NULL AND updated_at IS NULL)",
+		},
+		{
+			name:    "unknown operator",
+			where:   &operatorParam{BadOpt: P(1)},
+			wantErr: ErrUnsupportedOperator,
+		},
+		{
+			name:    "bad between",
+			where:   &operatorParam{BadBetween: []int{1, 2, 3}},
+			wantErr: errAny,
+		},
+		{
+			name:    "bad prefix",
+			where:   &operatorParam{BadPrefix: P(1)},
+			wantErr: errAny,
+		},
+		{
+			name:    "bad is null",
+			where:   &operatorParam{BadIsNull: P(1)},
+			wantErr: errAny,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := (&Builder{}).BuildQuery("students", nil, tt.where)
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr != errAny && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("BuildQuery() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("BuildQuery() sql = \n`%v`, want \n`%v`", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("BuildQuery() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// errAny means any error is expected
+var errAny = errors.New("any error")
+
+type groupParam struct {
+	Status *int             `db:"status"`
+	Or     *groupOrParam    `db:"_or"`
+	OrAge  *groupOrAgeParam `db:"_or_age"`
+}
+
+type groupOrParam struct {
+	Status  *int           `db:"status"`
+	OwnerID *int64         `db:"owner_id"`
+	And     *groupAndParam `db:"_and"`
+}
+
+type groupAndParam struct {
+	Name *string       `db:"name,prefix"`
+	Or   *groupOrParam `db:"_or"`
+}
+
+type groupOrAgeParam struct {
+	Age    *int `db:"age,<"`
+	AgeGte *int `db:"age,>="`
+}
+
+func TestBuildGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		where    *groupParam
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "or",
+			where:    &groupParam{Status: P(0), Or: &groupOrParam{Status: P(1), OwnerID: P(int64(2))}},
+			wantSQL:  "SELECT * FROM students WHERE (status=? AND (owner_id=? OR status=?))",
+			wantArgs: []any{0, int64(2), 1},
+		},
+		{
+			name:    "empty group",
+			where:   &groupParam{Or: &groupOrParam{And: &groupAndParam{}}},
+			wantSQL: "SELECT * FROM students",
+		},
+		{
+			name: "nested",
+			where: &groupParam{
+				Or: &groupOrParam{
+					Status: P(1),
+					And: &groupAndParam{
+						Name: P("n"),
+						Or:   &groupOrParam{OwnerID: P(int64(2)), Status: P(3)},
+					},
+				},
+				OrAge: &groupOrAgeParam{Age: P(10), AgeGte: P(60)},
+			},
+			wantSQL:  "SELECT * FROM students WHERE ((status=? OR (name LIKE ? ESCAPE '!' AND (owner_id=? OR status=?))) AND (age>=? OR age<?))",
+			wantArgs: []any{1, "n%", int64(2), 3, 60, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := (&Builder{}).BuildQuery("students", nil, tt.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("BuildQuery() sql = \n`%v`, want \n`%v`", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("BuildQuery() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: Postgres}).BuildDelete("students", &groupParam{Status: P(0), Or: &groupOrParam{Status: P(1), OwnerID: P(int64(2))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `DELETE FROM "students" WHERE ("status"=$1 AND ("owner_id"=$2 OR "status"=$3))`; gotSQL != want {
+		t.Errorf("BuildDelete() sql = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{0, int64(2), 1}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildDelete() args = %v, want %v", gotArgs, want)
+	}
+}
+
+type exprParam struct {
+	ID        *int64 `db:"id"`
+	Count     *Expr  `db:"count"`
+	UpdatedAt *Expr  `db:"updated_at,<"`
+	Attrs     *Expr  `db:"_expr"`
+	Zero      Expr   `db:"zero"`
+	BadIn     *Expr  `db:"bad,in"`
+	BadExpr   *int   `db:"_expr_bad"`
+}
+
+func TestBuildExpr(t *testing.T) {
+	runGoldenCases(t, &Builder{}, []goldenCase{
+		{
+			name: "update",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdate("t", &exprParam{
+					ID:        P(int64(1)),
+					UpdatedAt: &Expr{SQL: "NOW()"},
+					Attrs:     &Expr{SQL: "JSON_EXTRACT(attrs, '$.k')=?", Args: []any{"v"}},
+				}, &exprParam{
+					Count:     &Expr{SQL: ExprColumn + "+?", Args: []any{2}},
+					UpdatedAt: &Expr{SQL: "NOW()"},
+					Attrs:     &Expr{SQL: "ignored"},
+				})
+			},
+			wantSQL:  "UPDATE t SET count=count+?,updated_at=NOW() WHERE (id=? AND updated_at<NOW() AND (JSON_EXTRACT(attrs, '$.k')=?))",
+			wantArgs: []any{2, int64(1), "v"},
+		},
+		{
+			name: "insert",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildInsert("t", CommonInsert, &exprParam{ID: P(int64(1)), UpdatedAt: &Expr{SQL: "NOW()"}})
+			},
+			wantSQL:  "INSERT INTO t (id,updated_at) VALUES (?,NOW())",
+			wantArgs: []any{int64(1)},
+		},
+		{
+			name: "bad operator",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("t", nil, &exprParam{BadIn: &Expr{SQL: "1"}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad expr",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("t", nil, &exprParam{BadExpr: P(1)})
+			},
+			wantErr: true,
+		},
+	})
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: Postgres}).BuildUpdate("t",
+		&exprParam{ID: P(int64(1)), Attrs: &Expr{SQL: "attrs->>'k'=?", Args: []any{"v"}}},
+		&exprParam{Count: &Expr{SQL: ExprColumn + "+?", Args: []any{2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `UPDATE "t" SET "count"="count"+$1 WHERE ("id"=$2 AND (attrs->>'k'=$3))`; gotSQL != want {
+		t.Errorf("BuildUpdate() sql = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{2, int64(1), "v"}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildUpdate() args = %v, want %v", gotArgs, want)
+	}
+}
+
+type orderParam struct {
+	Age     *int         `db:"age,>"`
+	OrderBy any          `db:"_orderby"`
+	GroupBy any          `db:"_groupby"`
+	Having  *orderHaving `db:"_having"`
+}
+
+type orderHaving struct {
+	Count *int `db:"count(*),>"`
+	Sum   *int `db:"SUM(score),<"`
+	Bad   *int `db:"count(password),>"`
+}
+
+func TestBuildOrderBy(t *testing.T) {
+	columns := []string{"id", "name", "status", "score"}
+	tests := []struct {
+		name     string
+		columns  []string
+		where    *orderParam
+		wantSQL  string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:    "typed",
+			columns: columns,
+			where:   &orderParam{OrderBy: OrderBy{{Column: "status", Desc: true}, {Column: "id"}}},
+			wantSQL: `SELECT * FROM "students" ORDER BY "status" DESC,"id" ASC`,
+		},
+		{
+			name:    "typed not allowed",
+			columns: columns,
+			where:   &orderParam{OrderBy: OrderBy{{Column: "password"}}},
+			wantErr: ErrColumnNotAllowed,
+		},
+		{
+			name:    "typed empty",
+			columns: columns,
+			where:   &orderParam{OrderBy: OrderBy{}},
+			wantErr: errAny,
+		},
+		{
+			name:    "string",
+			columns: columns,
+			where:   &orderParam{OrderBy: "id desc, name asc"},
+			wantSQL: `SELECT * FROM "students" ORDER BY "id" DESC,"name" ASC`,
+		},
+		{
+			name:    "string not allowed",
+			columns: columns,
+			where:   &orderParam{OrderBy: "password desc"},
+			wantErr: ErrColumnNotAllowed,
+		},
+		{
+			name:    "string injection without columns",
+			where:   &orderParam{OrderBy: "(select(1)) desc"},
+			wantErr: ErrColumnNotAllowed,
+		},
+		{
+			name:    "string without columns",
+			where:   &orderParam{OrderBy: "password desc"},
+			wantSQL: `SELECT * FROM "students" ORDER BY "password" DESC`,
+		},
+		{
+			name:    "group by",
+			columns: columns,
+			where: &orderParam{
+				Age:     P(1),
+				GroupBy: "status, name",
+				Having:  &orderHaving{Count: P(1), Sum: P(100)},
+			},
+			wantSQL:  `SELECT * FROM "students" WHERE ("age">$1) GROUP BY "status","name" HAVING (count(*)>$2 AND SUM(score)<$3)`,
+			wantArgs: []any{1, 1, 100},
+		},
+		{
+			name:    "group by slice",
+			columns: columns,
+			where:   &orderParam{GroupBy: []string{"status"}},
+			wantSQL: `SELECT * FROM "students" GROUP BY "status"`,
+		},
+		{
+			name:    "group by not allowed",
+			columns: columns,
+			where:   &orderParam{GroupBy: "status, password"},
+			wantErr: ErrColumnNotAllowed,
+		},
+		{
+			name:    "group by injection",
+			columns: columns,
+			where:   &orderParam{GroupBy: "status; DROP TABLE students"},
+			wantErr: ErrColumnNotAllowed,
+		},
+		{
+			name:    "having not allowed",
+			columns: columns,
+			where:   &orderParam{GroupBy: "status", Having: &orderHaving{Bad: P(1)}},
+			wantErr: ErrColumnNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Builder{Dialect: Postgres, Columns: tt.columns}
+			gotSQL, gotArgs, err := b.BuildQuery("students", nil, tt.where)
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr != errAny && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("BuildQuery() err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("BuildQuery() sql = \n`%v`, want \n`%v`", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("BuildQuery() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectExprField(t *testing.T) {
+	columns := []string{"id", "rn"}
+	tests := []struct {
+		expr    SelectExpr
+		columns []string
+		want    string
+		wantErr error
+	}{
+		{expr: SelectExpr{SQL: "ROW_NUMBER() OVER (ORDER BY id)", Alias: "rn"}, columns: columns, want: "ROW_NUMBER() OVER (ORDER BY id) AS rn"},
+		{expr: SelectExpr{SQL: "COUNT(*)", Alias: "cnt"}, want: "COUNT(*) AS cnt"},
+		{expr: SelectExpr{SQL: "COUNT(*)", Alias: "cnt"}, columns: columns, wantErr: ErrColumnNotAllowed},
+		{expr: SelectExpr{SQL: "COUNT(*)", Alias: "cnt FROM t --"}, wantErr: ErrColumnNotAllowed},
+		{expr: SelectExpr{SQL: " ", Alias: "rn"}, columns: columns, wantErr: errAny},
+	}
+	for _, tt := range tests {
+		got, err := tt.expr.Field(tt.columns)
+		if tt.wantErr != nil {
+			if err == nil || (tt.wantErr != errAny && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("Field(%+v) err = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Field(%+v) = `%v`, %v, want `%v`", tt.expr, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package internal
+
+import "fmt"
+
+// the operators of subquery, the column of them is only a label
+const (
+	OpExists    = "exists"
+	OpNotExists = "not exists"
+)
+
+// SubQuery is the value of `in`, `not in`, `exists` and `not exists`, it is inlined as `(SELECT column FROM table WHERE ...)`
+type SubQuery struct {
+	Table string
+	// Column is the selected column, it's `1` for exists if empty
+	Column string
+	Where  any
+	// Columns are the columns of the Data of table, nil means all identifiers are allowed
+	Columns []string
+}
+
+// subQueryOperator reports whether the operator accepts a SubQuery value
+func subQueryOperator(op string) bool {
+	switch op {
+	case OpIn, OpNotIn, OpExists, OpNotExists:
+		return true
+	}
+	return false
+}
+
+// subQuery writes the subquery in parentheses, its columns are checked by its own columns
+func (w *sqlWriter) subQuery(sq SubQuery, exists bool) error {
+	saved := w.columns
+	defer func() { w.columns = saved }()
+	w.columns = nil
+	w.allow(sq.Columns)
+
+	w.write("(SELECT ")
+	switch {
+	case sq.Column != "":
+		if err := w.checkColumn(sq.Column); err != nil {
+			return err
+		}
+		w.quote(sq.Column)
+	case exists:
+		w.write("1")
+	default:
+		return fmt.Errorf("the column of subquery from %s is empty", sq.Table)
+	}
+	w.write(" FROM ")
+	w.quote(sq.Table)
+
+	wheres := struct2Where(TagName, sq.Where)
+	if wheres == nil {
+		return fmt.Errorf("the where of subquery from %s must be struct", sq.Table)
+	}
+	if err := w.clauses(wheres); err != nil {
+		return err
+	}
+	w.write(")")
+	return nil
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type subUserParam struct {
+	ID        *int64    `db:"id,>"`
+	OrderIDs  *SubQuery `db:"id,in"`
+	NotIDs    *SubQuery `db:"id,not in"`
+	HasOrders *SubQuery `db:"orders,exists"`
+	NoOrders  *SubQuery `db:"orders,not exists"`
+	BadIn     *int      `db:"id,exists"`
+	BadEq     *SubQuery `db:"id,="`
+}
+
+type subOrderParam struct {
+	Status  *int     `db:"status"`
+	Amount  *int     `db:"amount,>="`
+	Expr    *Expr    `db:"_expr"`
+	OrderBy *string  `db:"_orderby"`
+	Limit   []uint   `db:"_limit"`
+	GroupBy []string `db:"_groupby"`
+}
+
+var subOrderColumns = []string{"id", "user_id", "amount", "status"}
+
+func TestBuildSubQuery(t *testing.T) {
+	runGoldenCases(t, &Builder{Columns: []string{"id", "name"}}, []goldenCase{
+		{
+			name: "in",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					ID: P(int64(1)),
+					OrderIDs: &SubQuery{Table: "orders", Column: "user_id", Columns: subOrderColumns, Where: &subOrderParam{
+						Status:  P(1),
+						OrderBy: P("amount desc"),
+						Limit:   []uint{10},
+					}},
+				})
+			},
+			wantSQL:  "SELECT * FROM users WHERE (id IN (SELECT user_id FROM orders WHERE (status=?) ORDER BY amount DESC LIMIT ?,?) AND id>?)",
+			wantArgs: []any{1, 0, 10, int64(1)},
+		},
+		{
+			name: "not in",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					NotIDs: &SubQuery{Table: "orders", Column: "user_id", Columns: subOrderColumns, Where: &subOrderParam{Amount: P(100)}},
+				})
+			},
+			wantSQL:  "SELECT * FROM users WHERE (id NOT IN (SELECT user_id FROM orders WHERE (amount>=?)))",
+			wantArgs: []any{100},
+		},
+		{
+			name: "exists",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					HasOrders: &SubQuery{Table: "orders", Columns: subOrderColumns, Where: &subOrderParam{
+						Status: P(1),
+						Expr:   &Expr{SQL: "orders.user_id=users.id"},
+					}},
+					NoOrders: &SubQuery{Table: "orders", Columns: subOrderColumns, Where: &subOrderParam{
+						Amount: P(100),
+						Expr:   &Expr{SQL: "orders.user_id=users.id"},
+					}},
+				})
+			},
+			wantSQL:  "SELECT * FROM users WHERE (EXISTS (SELECT 1 FROM orders WHERE (status=? AND (orders.user_id=users.id))) AND NOT EXISTS (SELECT 1 FROM orders WHERE (amount>=? AND (orders.user_id=users.id))))",
+			wantArgs: []any{1, 100},
+		},
+		{
+			name: "zero subquery is skipped",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{OrderIDs: &SubQuery{}})
+			},
+			wantSQL: "SELECT * FROM users",
+		},
+		{
+			name: "column not allowed",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					OrderIDs: &SubQuery{Table: "orders", Column: "name", Columns: subOrderColumns},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "group by not allowed",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					OrderIDs: &SubQuery{Table: "orders", Column: "user_id", Columns: subOrderColumns, Where: &subOrderParam{GroupBy: []string{"name"}}},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "in without column",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{OrderIDs: &SubQuery{Table: "orders"}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "exists without subquery",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{BadIn: P(1)})
+			},
+			wantErr: true,
+		},
+		{
+			name: "subquery with comparison",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildQuery("users", nil, &subUserParam{
+					BadEq: &SubQuery{Table: "orders", Column: "user_id", Where: &subOrderParam{Status: P(1)}},
+				})
+			},
+			wantErr: true,
+		},
+	})
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: Postgres}).BuildUpdate("users",
+		&subUserParam{
+			ID:       P(int64(1)),
+			OrderIDs: &SubQuery{Table: "orders", Column: "user_id", Where: &subOrderParam{Status: P(1)}},
+		},
+		&struct {
+			Name *string `db:"name"`
+		}{Name: P("n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `UPDATE "users" SET "name"=$1 WHERE ("id" IN (SELECT "user_id" FROM "orders" WHERE ("status"=$2)) AND "id">$3)`; gotSQL != want {
+		t.Errorf("BuildUpdate() sql = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{"n", 1, int64(1)}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildUpdate() args = %v, want %v", gotArgs, want)
+	}
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type updateListParam struct {
+	ID     *int64  `db:"id"`
+	Name   *string `db:"name"`
+	Status *int    `db:"status"`
+	Count  *Expr   `db:"count"`
+}
+
+func TestBuildUpdateList(t *testing.T) {
+	runGoldenCases(t, &Builder{}, []goldenCase{
+		{
+			name: "update list",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdateList("t", "id",
+					&updateListParam{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+					&updateListParam{ID: P(int64(2)), Status: P(2), Count: &Expr{SQL: ExprColumn + "+?", Args: []any{1}}},
+					&updateListParam{ID: P(int64(3)), Name: P("n3")},
+				)
+			},
+			wantSQL: "UPDATE t SET count=CASE id WHEN ? THEN count+? ELSE count END," +
+				"name=CASE id WHEN ? THEN ? WHEN ? THEN ? ELSE name END," +
+				"status=CASE id WHEN ? THEN ? WHEN ? THEN ? ELSE status END WHERE id IN (?,?,?)",
+			wantArgs: []any{int64(2), 1, int64(1), "n1", int64(3), "n3", int64(1), 1, int64(2), 2, int64(1), int64(2), int64(3)},
+		},
+		{
+			name: "key missing",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdateList("t", "id", &updateListParam{ID: P(int64(1)), Name: P("n1")}, &updateListParam{Name: P("n2")})
+			},
+			wantErr: true,
+		},
+		{
+			name: "only key",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdateList("t", "id", &updateListParam{ID: P(int64(1))})
+			},
+			wantErr: true,
+		},
+		{
+			name: "expr key",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdateList("t", "count", &updateListParam{Name: P("n1"), Count: &Expr{SQL: "1"}})
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			build: func(b *Builder) (string, []any, error) {
+				return b.BuildUpdateList("t", "id")
+			},
+			wantErr: true,
+		},
+	})
+
+	gotSQL, gotArgs, err := (&Builder{Dialect: Postgres}).BuildUpdateList("t", "id",
+		&updateListParam{ID: P(int64(1)), Name: P("n1")},
+		&updateListParam{ID: P(int64(2)), Name: P("n2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `UPDATE "t" SET "name"=CASE "id" WHEN $1 THEN $2 WHEN $3 THEN $4 ELSE "name" END WHERE "id" IN ($5,$6)`; gotSQL != want {
+		t.Errorf("BuildUpdateList() sql = `%v`, want `%v`", gotSQL, want)
+	}
+	if want := []any{int64(1), "n1", int64(2), "n2", int64(1), int64(2)}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("BuildUpdateList() args = %v, want %v", gotArgs, want)
+	}
+}
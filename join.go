@@ -0,0 +1,107 @@
+package sqlmy
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// JoinTable is one table of join query, it is created by CURD.Where
+type JoinTable struct {
+	table     internal.JoinTable
+	newOption func(ctx context.Context, opts ...curdOpt) *curdOption
+}
+
+// Where returns the JoinTable of curd whose conditions are where
+func (curd *CURD[Data, Param]) Where(where *Param) *JoinTable {
+	return &JoinTable{
+		table: internal.JoinTable{
+			Table:   curd.table,
+			Columns: internal.Columns(reflect.TypeOf((*Data)(nil))),
+			Where:   where,
+		},
+		newOption: curd.newOption,
+	}
+}
+
+// JoinOn is the condition of join, see On
+type JoinOn = internal.JoinOn
+
+// On returns the join condition `left=right`, the unqualified left column belongs to the first table,
+// and the unqualified right column belongs to the joined table
+func On(left, right string) JoinOn {
+	return JoinOn{Left: left, Right: right}
+}
+
+// Join builds the query joining tables, see NewJoin
+type Join struct {
+	tables    []internal.JoinTable
+	newOption func(ctx context.Context, opts ...curdOpt) *curdOption
+}
+
+// NewJoin returns the Join whose first table is base, the options of base's CURD are used by the query
+func NewJoin(base *JoinTable) *Join {
+	return &Join{
+		tables:    []internal.JoinTable{base.table},
+		newOption: base.newOption,
+	}
+}
+
+// InnerJoin joins table by `INNER JOIN`
+func (j *Join) InnerJoin(table *JoinTable, on ...JoinOn) *Join {
+	return j.join(internal.InnerJoin, table, on)
+}
+
+// LeftJoin joins table by `LEFT JOIN`
+func (j *Join) LeftJoin(table *JoinTable, on ...JoinOn) *Join {
+	return j.join(internal.LeftJoin, table, on)
+}
+
+func (j *Join) join(kind string, table *JoinTable, on []JoinOn) *Join {
+	t := table.table
+	t.Kind, t.On = kind, on
+	j.tables = append(j.tables, t)
+	return j
+}
+
+// QueryJoin queries the joined tables and scans the rows into Result,
+// the columns of Result should be qualified by table: a flat struct with tags like `db:"orders.id"`,
+// or a struct whose fields are the Data of tables tagged like `db:"orders.,inline"`.
+// The conditions of every table are joined by AND, the `_orderby`, `_groupby` and `_limit` of the first table are used.
+func QueryJoin[Result any](ctx context.Context, j *Join, opts ...curdOpt) ([]*Result, error) {
+	begin := time.Now()
+	option := j.newOption(ctx, opts...)
+	table := j.tables[0].Table
+
+	query, args, err := option.builder.BuildJoinQuery(j.tables, internal.Columns(reflect.TypeOf((*Result)(nil))))
+	if err == nil {
+		query, err = option.lockSQL(ctx, query)
+	}
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryJoinBuild] table[%s] err[%v]", costMs(begin), table, err)
+		return nil, err
+	}
+	query = commentSQL(ctx, option.sqlComment(), query)
+	logSQL(ctx, "[QueryJoinBuild]", query, args)
+
+	rows, err := curdQuery(ctx, query, args)
+	if err != nil {
+		logger.Error(ctx, "cost[%d] [QueryJoinQuery] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+		return nil, err
+	}
+
+	var results []*Result
+	if rows != nil {
+		results = []*Result{}
+		err = option.rowsScan(rows, &results)
+		if err != nil {
+			logger.Error(ctx, "cost[%d] [QueryJoinScan] sql[%s] err[%v]", costMs(begin), sqlDeal(query), err)
+			return nil, err
+		}
+	}
+
+	logger.Info(ctx, "cost[%d] [QueryJoinSucc] table[%s] len[%d]", costMs(begin), table, len(results))
+	return results, nil
+}
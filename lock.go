@@ -0,0 +1,71 @@
+package sqlmy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// ErrLockOutsideTx is returned by the locking read outside TxExec, the lock is released at once in autocommit
+var ErrLockOutsideTx = errors.New("locking read must be in a transaction, call TxExec at first")
+
+// ErrLockUnsupported is returned by the aggregate and compound queries with WithForUpdate or WithShareLock,
+// the rows they read can not be locked in all dialects
+var ErrLockUnsupported = errors.New("locking read is not supported by aggregate and compound queries")
+
+// LockWait is the behavior of locking read on the rows locked by others, waiting by default
+type LockWait = internal.LockWait
+
+const (
+	// NoWait fails at once if the rows are locked
+	NoWait LockWait = internal.LockNoWait
+	// SkipLocked skips the locked rows
+	SkipLocked LockWait = internal.LockSkipLocked
+)
+
+// WithForUpdate makes the query a locking read by `FOR UPDATE`, wait is NoWait or SkipLocked.
+// It must be used in TxExec, or ErrLockOutsideTx is returned
+func WithForUpdate(wait ...LockWait) curdOpt {
+	return withLock(internal.LockForUpdate, wait)
+}
+
+// WithShareLock makes the query a locking read by `LOCK IN SHARE MODE` of MySQL or `FOR SHARE`, wait is NoWait or SkipLocked.
+// It must be used in TxExec, or ErrLockOutsideTx is returned
+func WithShareLock(wait ...LockWait) curdOpt {
+	return withLock(internal.LockShare, wait)
+}
+
+func withLock(mode internal.LockMode, wait []LockWait) curdOpt {
+	return func(co *curdOption) {
+		co.lockMode = mode
+		co.lockWait = internal.LockWaitDefault
+		if len(wait) > 0 {
+			co.lockWait = wait[0]
+		}
+	}
+}
+
+// lockSQL appends the locking clause to query, it fails outside transaction except in dry run
+func (co *curdOption) lockSQL(ctx context.Context, query string) (string, error) {
+	if co.lockMode == internal.LockNone {
+		return query, nil
+	}
+	if !inTx(ctx) && GetRecorder(ctx) == nil {
+		return "", ErrLockOutsideTx
+	}
+
+	clause, err := co.dialect.LockClause(co.lockMode, co.lockWait)
+	if err != nil {
+		return "", err
+	}
+	return query + clause, nil
+}
+
+// checkNoLock fails the query which can not be a locking read instead of ignoring the lock
+func (co *curdOption) checkNoLock() error {
+	if co.lockMode != internal.LockNone {
+		return ErrLockUnsupported
+	}
+	return nil
+}
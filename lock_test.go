@@ -0,0 +1,125 @@
+package sqlmy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func ExampleWithForUpdate() {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(`SELECT id,name,status FROM students WHERE \(id=\?\) FOR UPDATE SKIP LOCKED`).
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 2),
+		)
+	mock.
+		ExpectExec(`UPDATE students SET status=\? WHERE \(id=\?\)`).
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	err = TxExec(ctx, func(ctx context.Context) error {
+		student, err := StudentCURD.Query(ctx, &StudentParam{ID: P(int64(1))}, WithForUpdate(SkipLocked))
+		if err != nil {
+			return err
+		}
+		_, err = StudentCURD.Update(ctx, &StudentParam{ID: P(student.ID)}, &StudentParam{Status: P(student.Status - 1)})
+		return err
+	})
+	fmt.Println(err)
+
+	_, err = StudentCURD.Query(ctx, &StudentParam{ID: P(int64(1))}, WithForUpdate())
+	fmt.Println(err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: <nil>
+	// locking read must be in a transaction, call TxExec at first
+}
+
+func TestWithShareLock(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(`SELECT "id","name","status" FROM "students" WHERE ("id"=$1) FOR SHARE NOWAIT`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "N1", 2))
+	mock.ExpectCommit()
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = TxExec(ctx, func(ctx context.Context) error {
+		_, err := PGStudentCURD.Query(ctx, &StudentParam{ID: P(int64(1))}, WithShareLock(NoWait))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := PGStudentCURD.QueryList(ctx, nil, WithShareLock()); !errors.Is(err, ErrLockOutsideTx) {
+		t.Errorf("QueryList() err = %v, want %v", err, ErrLockOutsideTx)
+	}
+
+	dryCtx := WithDryRun(context.Background())
+	if _, err := StudentCURD.QueryList(dryCtx, nil, WithShareLock()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := GetRecorder(dryCtx).Statements()[0].SQL, "SELECT id,name,status FROM students LOCK IN SHARE MODE"; got != want {
+		t.Errorf("dry run sql = `%v`, want `%v`", got, want)
+	}
+}
+
+func TestLockUnsupported(t *testing.T) {
+	ctx := WithDryRun(context.Background())
+
+	if _, err := StudentCURD.Count(ctx, nil, WithForUpdate()); !errors.Is(err, ErrLockUnsupported) {
+		t.Errorf("Count() err = %v, want %v", err, ErrLockUnsupported)
+	}
+	if _, err := StudentCURD.Max(ctx, "id", nil, WithShareLock()); !errors.Is(err, ErrLockUnsupported) {
+		t.Errorf("Max() err = %v, want %v", err, ErrLockUnsupported)
+	}
+	type statusCount struct {
+		Status int   `db:"status"`
+		Total  int64 `db:"total" agg:"count(*)"`
+	}
+	if _, err := QueryGroup[statusCount](ctx, StudentCURD, []string{"status"}, nil, WithForUpdate()); !errors.Is(err, ErrLockUnsupported) {
+		t.Errorf("QueryGroup() err = %v, want %v", err, ErrLockUnsupported)
+	}
+	_, err := StudentCURD.Select(nil).UnionAll(StudentCURD.Select(nil, WithForUpdate(SkipLocked))).QueryList(ctx)
+	if !errors.Is(err, ErrLockUnsupported) {
+		t.Errorf("Compound.QueryList() err = %v, want %v", err, ErrLockUnsupported)
+	}
+	if _, err := StudentCURD.Select(nil).QueryList(ctx, WithShareLock()); !errors.Is(err, ErrLockUnsupported) {
+		t.Errorf("Compound.QueryList() err = %v, want %v", err, ErrLockUnsupported)
+	}
+	if stmts := GetRecorder(ctx).Statements(); len(stmts) != 0 {
+		t.Errorf("Statements() = %v, want none", stmts)
+	}
+}
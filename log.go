@@ -12,19 +12,40 @@ func SetLogger(log Logger) {
 	logger = log
 }
 
+// LogLevel is the severity of one log line, a bigger value means more severe
 type LogLevel int
 
-var (
-	LogLevelDebug LogLevel = -0
-	LogLevelInfo  LogLevel = 0
-	LogLevelError LogLevel = 1
+const (
+	LogLevelDebug LogLevel = 0
+	LogLevelInfo  LogLevel = 1
+	LogLevelWarn  LogLevel = 2
+	LogLevelError LogLevel = 3
 )
 
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// Logger is a leveled logger
+// ValidateLevel reports whether the log of level will be output
 type Logger interface {
 	SetLevel(level LogLevel)
 	ValidateLevel(level LogLevel) bool
 
+	Debug(ctx context.Context, format string, args ...interface{})
 	Info(ctx context.Context, format string, args ...interface{})
+	Warn(ctx context.Context, format string, args ...interface{})
 	Error(ctx context.Context, format string, args ...interface{})
 }
 
@@ -37,21 +58,33 @@ type DumbLogger struct{}
 
 func (dl *DumbLogger) SetLevel(level LogLevel)                                       {}
 func (dl *DumbLogger) ValidateLevel(level LogLevel) bool                             { return false }
+func (dl *DumbLogger) Debug(ctx context.Context, format string, args ...interface{}) {}
 func (dl *DumbLogger) Info(ctx context.Context, format string, args ...interface{})  {}
+func (dl *DumbLogger) Warn(ctx context.Context, format string, args ...interface{})  {}
 func (dl *DumbLogger) Error(ctx context.Context, format string, args ...interface{}) {}
 
+// StdLogger output logs whose level is not less than the level set by SetLevel
+// the log will be routed to LevelLoggers[level] if exist, or Logger, or log.Default()
 type StdLogger struct {
-	Logger   *log.Logger
+	Logger       *log.Logger
+	LevelLoggers map[LogLevel]*log.Logger
+
 	logLevel LogLevel
 }
 
 func (sl *StdLogger) SetLevel(level LogLevel) { sl.logLevel = level }
 func (sl *StdLogger) ValidateLevel(level LogLevel) bool {
-	return sl.logLevel >= level
+	return level >= sl.logLevel
+}
+func (sl *StdLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	sl.output(ctx, LogLevelDebug, format, args...)
 }
 func (sl *StdLogger) Info(ctx context.Context, format string, args ...interface{}) {
 	sl.output(ctx, LogLevelInfo, format, args...)
 }
+func (sl *StdLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	sl.output(ctx, LogLevelWarn, format, args...)
+}
 func (sl *StdLogger) Error(ctx context.Context, format string, args ...interface{}) {
 	sl.output(ctx, LogLevelError, format, args...)
 }
@@ -60,17 +93,32 @@ func (sl *StdLogger) output(ctx context.Context, level LogLevel, format string,
 		return
 	}
 
-	logPrefix := `[INFO]`
+	logPrefix := "[" + level.String() + "] "
 	if logID := GetLogID(ctx); logID != "" {
-		logPrefix = "[INFO] [" + logID + "]"
+		logPrefix += "[" + logID + "] "
 	}
 	format = logPrefix + format
 
-	logger := sl.Logger
+	logger := sl.LevelLoggers[level]
+	if logger == nil {
+		logger = sl.Logger
+	}
 	if logger == nil {
 		logger = log.Default()
 	}
 
-	format = format + `\n`
 	logger.Output(4, fmt.Sprintf(format, args...))
 }
+
+// logSQL output sql and args at debug level
+func logSQL(ctx context.Context, action string, query string, args []any) {
+	if !logger.ValidateLevel(LogLevelDebug) {
+		return
+	}
+
+	if logRenderSQL {
+		logger.Debug(ctx, "%s sql[%s]", action, sqlRender(query, args))
+		return
+	}
+	logger.Debug(ctx, "%s sql[%s] args[%v]", action, sqlDeal(query), argsDeal(args))
+}
@@ -0,0 +1,92 @@
+package sqlmy
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		level LogLevel
+		log   func(l Logger, ctx context.Context)
+		want  string
+	}{
+		{
+			name:  "debug",
+			level: LogLevelDebug,
+			log:   func(l Logger, ctx context.Context) { l.Debug(ctx, "a[%d]", 1) },
+			want:  "[DEBUG] a[1]\n",
+		},
+		{
+			name:  "debug filtered",
+			level: LogLevelInfo,
+			log:   func(l Logger, ctx context.Context) { l.Debug(ctx, "a[%d]", 1) },
+			want:  "",
+		},
+		{
+			name:  "info",
+			level: LogLevelInfo,
+			log:   func(l Logger, ctx context.Context) { l.Info(ctx, "a[%d]", 1) },
+			want:  "[INFO] a[1]\n",
+		},
+		{
+			name:  "warn",
+			level: LogLevelInfo,
+			log:   func(l Logger, ctx context.Context) { l.Warn(ctx, "a[%d]", 1) },
+			want:  "[WARN] a[1]\n",
+		},
+		{
+			name:  "error",
+			level: LogLevelError,
+			log:   func(l Logger, ctx context.Context) { l.Error(ctx, "a[%d]", 1) },
+			want:  "[ERROR] a[1]\n",
+		},
+		{
+			name:  "info filtered",
+			level: LogLevelError,
+			log:   func(l Logger, ctx context.Context) { l.Info(ctx, "a[%d]", 1) },
+			want:  "",
+		},
+		{
+			name:  "log id",
+			level: LogLevelDebug,
+			log:   func(l Logger, ctx context.Context) { l.Info(WithLogID(ctx, "lid"), "a[%d]", 1) },
+			want:  "[INFO] [lid] a[1]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			l := &StdLogger{Logger: log.New(buf, "", 0)}
+			l.SetLevel(tt.level)
+			tt.log(l, context.Background())
+			if got := buf.String(); got != tt.want {
+				t.Errorf("StdLogger output `%v`, want `%v`", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdLoggerLevelLoggers(t *testing.T) {
+	common, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	l := &StdLogger{
+		Logger: log.New(common, "", 0),
+		LevelLoggers: map[LogLevel]*log.Logger{
+			LogLevelError: log.New(errs, "", 0),
+		},
+	}
+
+	ctx := context.Background()
+	l.Info(ctx, "i")
+	l.Error(ctx, "e")
+
+	if got, want := common.String(), "[INFO] i\n"; got != want {
+		t.Errorf("common output `%v`, want `%v`", got, want)
+	}
+	if got, want := errs.String(), "[ERROR] e\n"; got != want {
+		t.Errorf("error output `%v`, want `%v`", got, want)
+	}
+}
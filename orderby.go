@@ -0,0 +1,23 @@
+package sqlmy
+
+import "github.com/liuximu/sqlmy/internal"
+
+// ErrColumnNotAllowed is returned when the column of order by, group by or having is not one of Data
+var ErrColumnNotAllowed = internal.ErrColumnNotAllowed
+
+// Order is one key of OrderBy, see Asc and Desc
+type Order = internal.Order
+
+// OrderBy is the typed value of Param's field tagged `db:"_orderby"`,
+// its columns must be the columns of Data, as well as the ones of `_groupby` and `_having`
+type OrderBy = internal.OrderBy
+
+// Asc orders by column ascending
+func Asc(column string) Order {
+	return Order{Column: column}
+}
+
+// Desc orders by column descending
+func Desc(column string) Order {
+	return Order{Column: column, Desc: true}
+}
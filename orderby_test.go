@@ -0,0 +1,57 @@
+package sqlmy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type StudentOrderParam struct {
+	Status  *int    `db:"status"`
+	OrderBy OrderBy `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
+}
+
+func ExampleOrderBy() {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		panic(err)
+	}
+
+	mock.
+		ExpectQuery(`SELECT id,name,status FROM students WHERE (status=?) ORDER BY name DESC,id ASC LIMIT ?,?`).
+		WithArgs(1, 0, 10).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(2, "N2", 1).AddRow(1, "N1", 1),
+		)
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		panic(err)
+	}
+
+	curd := NewCURD[Student, StudentOrderParam]("students")
+	students, err := curd.QueryList(ctx, &StudentOrderParam{
+		Status:  P(1),
+		OrderBy: OrderBy{Desc("name"), Asc("id")},
+		Limit:   []uint{0, 10},
+	})
+	fmt.Println(err)
+	for _, student := range students {
+		fmt.Println(student.ID, student.Name)
+	}
+
+	// the column not in Student is rejected
+	_, err = curd.QueryList(ctx, &StudentOrderParam{OrderBy: OrderBy{Desc("password")}})
+	fmt.Println(err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		fmt.Printf("there were unfulfilled expectations: %s\n", err)
+	}
+
+	// output: <nil>
+	// 2 N2
+	// 1 N1
+	// column not allowed: password
+}
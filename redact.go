@@ -0,0 +1,98 @@
+package sqlmy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// RedactMask replaces the sensitive args in logs
+const RedactMask = "******"
+
+var (
+	logArgMaxLen  = 256
+	logArgsMaxNum = 64
+)
+
+// SetLogArgsLimit limits args output in logs
+// string and []byte longer than maxLen will be truncated,
+// only the first maxNum args and placeholders of an IN list will be output
+// zero or negative value means no limit
+func SetLogArgsLimit(maxLen, maxNum int) {
+	logArgMaxLen = maxLen
+	logArgsMaxNum = maxNum
+}
+
+// RegisterSensitiveColumns marks the columns' args will be masked in logs for all tables
+// it's the same as tag a Param's field with `db:"column,sensitive"`
+func RegisterSensitiveColumns(columns ...string) {
+	internal.RegisterSensitiveColumns(columns...)
+}
+
+// UnregisterSensitiveColumns removes the columns registered by RegisterSensitiveColumns,
+// the fields tagged with `sensitive` are still masked
+func UnregisterSensitiveColumns(columns ...string) {
+	internal.UnregisterSensitiveColumns(columns...)
+}
+
+// argsDeal makes args safe and short enough for logs
+func argsDeal(args []any) []any {
+	n := len(args)
+	if logArgsMaxNum > 0 && n > logArgsMaxNum {
+		n = logArgsMaxNum
+	}
+
+	rst := make([]any, 0, n+1)
+	for _, arg := range args[:n] {
+		rst = append(rst, argDeal(arg))
+	}
+	if n < len(args) {
+		rst = append(rst, fmt.Sprintf("...(%d more)", len(args)-n))
+	}
+	return rst
+}
+
+func argDeal(arg any) any {
+	switch v := arg.(type) {
+	case internal.Sensitive:
+		return RedactMask
+	case string:
+		if logArgMaxLen > 0 && len(v) > logArgMaxLen {
+			return truncateString(v, logArgMaxLen) + fmt.Sprintf("...(%d bytes)", len(v))
+		}
+	case []byte:
+		if logArgMaxLen > 0 && len(v) > logArgMaxLen {
+			return fmt.Sprintf("[]byte(%d bytes)", len(v))
+		}
+	}
+
+	return arg
+}
+
+// truncateString cut s to no more than n bytes without breaking a utf8 character
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+var placeholderListReg = regexp.MustCompile(`\(\?(,\?)+\)`)
+
+// sqlDeal shortens the long placeholder list like `IN (?,?,...,?)` for logs
+func sqlDeal(query string) string {
+	if logArgsMaxNum <= 0 {
+		return query
+	}
+
+	return placeholderListReg.ReplaceAllStringFunc(query, func(list string) string {
+		num := strings.Count(list, "?")
+		if num <= logArgsMaxNum {
+			return list
+		}
+		return "(" + strings.Repeat("?,", logArgsMaxNum) + fmt.Sprintf("...(%d more))", num-logArgsMaxNum)
+	})
+}
@@ -0,0 +1,179 @@
+package sqlmy
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/liuximu/sqlmy/internal"
+)
+
+func TestArgsDeal(t *testing.T) {
+	defer SetLogArgsLimit(logArgMaxLen, logArgsMaxNum)
+	SetLogArgsLimit(4, 3)
+
+	tests := []struct {
+		name string
+		args []any
+		want []any
+	}{
+		{
+			name: "empty",
+			args: nil,
+			want: []any{},
+		},
+		{
+			name: "sensitive",
+			args: []any{1, internal.Sensitive{Value: "110"}},
+			want: []any{1, RedactMask},
+		},
+		{
+			name: "long string",
+			args: []any{"abcdef", "abcd"},
+			want: []any{"abcd...(6 bytes)", "abcd"},
+		},
+		{
+			name: "long utf8 string",
+			args: []any{"中文"},
+			want: []any{"中...(6 bytes)"},
+		},
+		{
+			name: "long bytes",
+			args: []any{[]byte("abcdef")},
+			want: []any{"[]byte(6 bytes)"},
+		},
+		{
+			name: "too many",
+			args: []any{1, 2, 3, 4, 5},
+			want: []any{1, 2, 3, "...(2 more)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := argsDeal(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("argsDeal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqlDeal(t *testing.T) {
+	defer SetLogArgsLimit(logArgMaxLen, logArgsMaxNum)
+	SetLogArgsLimit(0, 3)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "short",
+			query: "SELECT * FROM t WHERE (id IN (?,?,?))",
+			want:  "SELECT * FROM t WHERE (id IN (?,?,?))",
+		},
+		{
+			name:  "long",
+			query: "SELECT * FROM t WHERE (id IN (?,?,?,?,?))",
+			want:  "SELECT * FROM t WHERE (id IN (?,?,?,...(2 more)))",
+		},
+		{
+			name:  "values",
+			query: "INSERT INTO t (a,b,c,d) VALUES (?,?,?,?),(?,?,?,?)",
+			want:  "INSERT INTO t (a,b,c,d) VALUES (?,?,?,...(1 more)),(?,?,?,...(1 more))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sqlDeal(tt.query); got != tt.want {
+				t.Errorf("sqlDeal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type Account struct {
+	ID    int64  `db:"id"`
+	Phone string `db:"phone"`
+}
+
+type AccountParam struct {
+	ID    *int64  `db:"id"`
+	Phone *string `db:"phone,sensitive"`
+}
+
+func TestSensitiveLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(&StdLogger{Logger: log.New(buf, "", 0)})
+	defer SetLogger(&DumbLogger{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectExec(`UPDATE accounts SET phone=\? WHERE \(id=\?\)`).
+		WithArgs("120", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewCURD[Account, AccountParam]("accounts").Update(ctx,
+		&AccountParam{ID: P(int64(1))},
+		&AccountParam{Phone: P("120")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	if out := buf.String(); strings.Contains(out, "120") || !strings.Contains(out, RedactMask) {
+		t.Errorf("sensitive arg not masked: %s", out)
+	}
+}
+
+type AccountExprParam struct {
+	ID    *int64 `db:"id"`
+	Phone *Expr  `db:"phone,sensitive"`
+}
+
+func TestSensitiveExprLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(&StdLogger{Logger: log.New(buf, "", 0)})
+	defer SetLogger(&DumbLogger{})
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectExec(`UPDATE accounts SET phone=AES_ENCRYPT\(\?, 'k'\) WHERE \(id=\?\)`).
+		WithArgs("13800000000", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewCURD[Account, AccountExprParam]("accounts").Update(ctx,
+		&AccountExprParam{ID: P(int64(1))},
+		&AccountExprParam{Phone: P(NewExpr("AES_ENCRYPT(?, 'k')", "13800000000"))},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	if out := buf.String(); strings.Contains(out, "13800000000") || !strings.Contains(out, RedactMask) {
+		t.Errorf("sensitive expr arg not masked: %s", out)
+	}
+}
@@ -0,0 +1,294 @@
+package sqlmy
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+var logRenderSQL = false
+
+// SetLogRenderSQL makes debug logs output the sql rendered by RenderSQL instead of sql and args
+func SetLogRenderSQL(render bool) {
+	logRenderSQL = render
+}
+
+// renderLocation is the location the times are converted to by RenderSQL, like the `loc` of go-sql-driver/mysql
+var renderLocation = time.UTC
+
+// SetRenderLocation sets the location the times are rendered in, it should be the `loc` of the MySQL dsn, UTC by default
+func SetRenderLocation(loc *time.Location) {
+	renderLocation = loc
+}
+
+// RenderSQL interpolates args into the `?` placeholders of query with MySQL escaping,
+// or into the `$N` placeholders of Postgres with standard string escaping,
+// it is used for debugging, the result can be copied and run directly
+// placeholders in quoted strings, quoted identifiers and comments are not replaced
+func RenderSQL(query string, args ...any) (string, error) {
+	buf := strings.Builder{}
+	buf.Grow(len(query) + len(args)*8)
+
+	argIdx := 0
+	used := make([]bool, len(args))
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch c {
+		case '\'', '"', '`':
+			end := internal.SkipQuoted(query, i)
+			buf.WriteString(query[i:end])
+			i = end - 1
+		case '-', '#', '/':
+			end := skipComment(query, i)
+			if end == i {
+				buf.WriteByte(c)
+				continue
+			}
+			buf.WriteString(query[i:end])
+			i = end - 1
+		case '?':
+			if argIdx >= len(args) {
+				return "", fmt.Errorf("render sql: args not enough, want more than %d", len(args))
+			}
+			if err := renderArg(&buf, args[argIdx], false); err != nil {
+				return "", fmt.Errorf("render sql: arg[%d]: %w", argIdx, err)
+			}
+			used[argIdx] = true
+			argIdx++
+		case '$':
+			n, end := dollarPlaceholder(query, i)
+			if end == i {
+				buf.WriteByte(c)
+				continue
+			}
+			if n < 1 || n > len(args) {
+				return "", fmt.Errorf("render sql: placeholder $%d out of %d args", n, len(args))
+			}
+			if err := renderArg(&buf, args[n-1], true); err != nil {
+				return "", fmt.Errorf("render sql: arg[%d]: %w", n-1, err)
+			}
+			used[n-1] = true
+			i = end - 1
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	for i, ok := range used {
+		if !ok {
+			return "", fmt.Errorf("render sql: args too many, arg[%d] of %d is not used", i, len(args))
+		}
+	}
+	return buf.String(), nil
+}
+
+// dollarPlaceholder returns the number of the Postgres placeholder `$N` begin at i and the index after it,
+// the index is i if there is no placeholder, like `$` in the identifier `a$1`
+func dollarPlaceholder(query string, i int) (int, int) {
+	if i > 0 && isIdentByte(query[i-1]) {
+		return 0, i
+	}
+	j := i + 1
+	for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+		j++
+	}
+	if j == i+1 {
+		return 0, i
+	}
+	n, err := strconv.Atoi(query[i+1 : j])
+	if err != nil {
+		return 0, i
+	}
+	return n, j
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// skipComment returns the index after the comment begin at i, or i if there is no comment
+func skipComment(query string, i int) int {
+	rest := query[i:]
+	switch {
+	case strings.HasPrefix(rest, "#"), strings.HasPrefix(rest, "-- "):
+		if j := strings.IndexByte(rest, '\n'); j != -1 {
+			return i + j
+		}
+		return len(query)
+	case strings.HasPrefix(rest, "/*"):
+		if j := strings.Index(rest[2:], "*/"); j != -1 {
+			return i + 2 + j + 2
+		}
+		return len(query)
+	}
+	return i
+}
+
+const (
+	renderTimeFormat   = "2006-01-02 15:04:05.999999"
+	renderPGTimeFormat = "2006-01-02 15:04:05.999999-07:00"
+)
+
+// renderArg writes arg as literal, postgres renders strings, bytes and bools in the Postgres way
+func renderArg(buf *strings.Builder, arg any, postgres bool) error {
+	switch v := arg.(type) {
+	case nil:
+		buf.WriteString("NULL")
+	case internal.Sensitive:
+		return renderArg(buf, v.Value, postgres)
+	case string:
+		renderString(buf, v, postgres)
+	case []byte:
+		if v == nil {
+			buf.WriteString("NULL")
+			return nil
+		}
+		if postgres {
+			buf.WriteString(`'\x`)
+		} else {
+			buf.WriteString("X'")
+		}
+		buf.WriteString(hex.EncodeToString(v))
+		buf.WriteByte('\'')
+	case bool:
+		if postgres {
+			buf.WriteString(strings.ToUpper(strconv.FormatBool(v)))
+			return nil
+		}
+		if v {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	case time.Time:
+		if v.IsZero() && !postgres {
+			buf.WriteString("'0000-00-00'")
+			return nil
+		}
+		buf.WriteByte('\'')
+		if postgres {
+			// the offset is kept as the Postgres drivers send it
+			buf.WriteString(v.Format(renderPGTimeFormat))
+		} else {
+			buf.WriteString(v.In(renderLocation).Format(renderTimeFormat))
+		}
+		buf.WriteByte('\'')
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+		val, err := v.Value()
+		if err != nil {
+			return err
+		}
+		return renderArg(buf, val, postgres)
+	default:
+		return renderReflect(buf, reflect.ValueOf(arg), postgres)
+	}
+
+	return nil
+}
+
+func renderReflect(buf *strings.Builder, rv reflect.Value, postgres bool) error {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			buf.WriteString("NULL")
+			return nil
+		}
+		return renderArg(buf, rv.Elem().Interface(), postgres)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("unsupported float value: %v", f)
+		}
+		bitSize := 64
+		if rv.Kind() == reflect.Float32 {
+			bitSize = 32
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	case reflect.String:
+		renderString(buf, rv.String(), postgres)
+	case reflect.Bool:
+		return renderArg(buf, rv.Bool(), postgres)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return renderArg(buf, rv.Bytes(), postgres)
+		}
+		if rv.Len() == 0 {
+			buf.WriteString("NULL")
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := renderArg(buf, rv.Index(i).Interface(), postgres); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type: %T", rv.Interface())
+	}
+
+	return nil
+}
+
+// renderString writes the quoted string escaped as mysql_real_escape_string,
+// or with the quotes doubled for postgres whose strings are standard conforming
+func renderString(buf *strings.Builder, s string, postgres bool) {
+	if postgres {
+		buf.WriteByte('\'')
+		buf.WriteString(strings.ReplaceAll(s, "'", "''"))
+		buf.WriteByte('\'')
+		return
+	}
+	buf.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			buf.WriteString(`\0`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\x1a':
+			buf.WriteString(`\Z`)
+		case '\'':
+			buf.WriteString(`\'`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('\'')
+}
+
+// sqlRender returns the content of sql and args for debug logs
+func sqlRender(query string, args []any) string {
+	masked := make([]any, len(args))
+	for i, arg := range args {
+		masked[i] = argDeal(arg)
+	}
+
+	rendered, err := RenderSQL(query, masked...)
+	if err != nil {
+		return fmt.Sprintf("%s args%v render_err[%v]", sqlDeal(query), argsDeal(args), err)
+	}
+	return rendered
+}
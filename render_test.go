@@ -0,0 +1,256 @@
+package sqlmy
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type renderStatus int
+
+type renderValuer struct{ v string }
+
+func (rv *renderValuer) Value() (driver.Value, error) { return rv.v, nil }
+
+func TestRenderSQL(t *testing.T) {
+	tm := time.Date(2022, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	var nilValuer *renderValuer
+
+	tests := []struct {
+		name    string
+		query   string
+		args    []any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no args",
+			query: "SELECT * FROM t",
+			want:  "SELECT * FROM t",
+		},
+		{
+			name:  "int and string",
+			query: "SELECT * FROM t WHERE (id=? AND name=?)",
+			args:  []any{1, "n1"},
+			want:  "SELECT * FROM t WHERE (id=1 AND name='n1')",
+		},
+		{
+			name:  "escape string",
+			query: "SELECT ?",
+			args:  []any{"a'b\"c\\d\ne\rf\x00g\x1ah"},
+			want:  `SELECT 'a\'b\"c\\d\ne\rf\0g\Zh'`,
+		},
+		{
+			name:  "injection",
+			query: "SELECT * FROM t WHERE name=?",
+			args:  []any{"' OR 1=1 -- "},
+			want:  `SELECT * FROM t WHERE name='\' OR 1=1 -- '`,
+		},
+		{
+			name:  "utf8 string",
+			query: "SELECT ?",
+			args:  []any{"中文"},
+			want:  "SELECT '中文'",
+		},
+		{
+			name:  "bytes",
+			query: "SELECT ?",
+			args:  []any{[]byte("a'\x00")},
+			want:  "SELECT X'612700'",
+		},
+		{
+			name:  "nil",
+			query: "SELECT ?, ?, ?",
+			args:  []any{nil, (*int)(nil), []byte(nil)},
+			want:  "SELECT NULL, NULL, NULL",
+		},
+		{
+			name:  "pointer",
+			query: "SELECT ?, ?",
+			args:  []any{P(1), P("a")},
+			want:  "SELECT 1, 'a'",
+		},
+		{
+			name:  "number",
+			query: "SELECT ?, ?, ?, ?, ?",
+			args:  []any{int8(-1), uint64(math.MaxUint64), 1.5, float32(0.1), renderStatus(2)},
+			want:  "SELECT -1, 18446744073709551615, 1.5, 0.1, 2",
+		},
+		{
+			name:  "bool",
+			query: "SELECT ?, ?",
+			args:  []any{true, false},
+			want:  "SELECT 1, 0",
+		},
+		{
+			name:  "time",
+			query: "SELECT ?, ?",
+			args:  []any{tm, time.Time{}},
+			want:  "SELECT '2022-01-02 03:04:05.6', '0000-00-00'",
+		},
+		{
+			name:  "non utc time",
+			query: "SELECT ?",
+			args:  []any{tm.In(time.FixedZone("CST", 8*3600))},
+			want:  "SELECT '2022-01-02 03:04:05.6'",
+		},
+		{
+			name:  "postgres non utc time",
+			query: "SELECT $1",
+			args:  []any{tm.In(time.FixedZone("CST", 8*3600))},
+			want:  "SELECT '2022-01-02 11:04:05.6+08:00'",
+		},
+		{
+			name:  "valuer",
+			query: "SELECT ?, ?, ?, ?",
+			args:  []any{sql.NullString{String: "a", Valid: true}, sql.NullInt64{}, &renderValuer{v: "b"}, nilValuer},
+			want:  "SELECT 'a', NULL, 'b', NULL",
+		},
+		{
+			name:  "slice",
+			query: "SELECT * FROM t WHERE id IN (?) AND name IN (?)",
+			args:  []any{[]int64{1, 2}, []string{"a", "b'"}},
+			want:  `SELECT * FROM t WHERE id IN (1,2) AND name IN ('a','b\'')`,
+		},
+		{
+			name:  "empty slice",
+			query: "SELECT * FROM t WHERE id IN (?)",
+			args:  []any{[]int64{}},
+			want:  "SELECT * FROM t WHERE id IN (NULL)",
+		},
+		{
+			name:  "quoted placeholder",
+			query: "SELECT '?', \"?\", `?`, 'a\\'?', 'b''?', ? FROM t",
+			args:  []any{1},
+			want:  "SELECT '?', \"?\", `?`, 'a\\'?', 'b''?', 1 FROM t",
+		},
+		{
+			name:  "comment placeholder",
+			query: "/* ? */ SELECT ? -- ?\n, ? # ?\n",
+			args:  []any{1, 2},
+			want:  "/* ? */ SELECT 1 -- ?\n, 2 # ?\n",
+		},
+		{
+			name:  "minus and divide",
+			query: "SELECT ?-1, ?/2",
+			args:  []any{3, 4},
+			want:  "SELECT 3-1, 4/2",
+		},
+		{
+			name:  "postgres",
+			query: `SELECT * FROM "t" WHERE ("id"=$1 AND "name" IN ($2,$3) AND "ok"=$4 AND "data"=$5 AND "at"=$6) OR "id"=$1`,
+			args:  []any{1, "a'b\\c", "d", true, []byte("a"), tm},
+			want:  `SELECT * FROM "t" WHERE ("id"=1 AND "name" IN ('a''b\c','d') AND "ok"=TRUE AND "data"='\x61' AND "at"='2022-01-02 03:04:05.6+00:00') OR "id"=1`,
+		},
+		{
+			name:  "postgres quoted placeholder",
+			query: `SELECT '$1', "$1", a$1, $1`,
+			args:  []any{1},
+			want:  `SELECT '$1', "$1", a$1, 1`,
+		},
+		{
+			name:    "postgres placeholder out of args",
+			query:   "SELECT $1, $2",
+			args:    []any{1},
+			wantErr: true,
+		},
+		{
+			name:    "postgres arg not used",
+			query:   "SELECT $2",
+			args:    []any{1, 2},
+			wantErr: true,
+		},
+		{
+			name:    "args not enough",
+			query:   "SELECT ?, ?",
+			args:    []any{1},
+			wantErr: true,
+		},
+		{
+			name:    "args too many",
+			query:   "SELECT ?",
+			args:    []any{1, 2},
+			wantErr: true,
+		},
+		{
+			name:    "nan",
+			query:   "SELECT ?",
+			args:    []any{math.NaN()},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported",
+			query:   "SELECT ?",
+			args:    []any{struct{}{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderSQL(tt.query, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderSQL() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderSQL() = `%v`, want `%v`", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogRenderSQL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(&StdLogger{Logger: log.New(buf, "", 0)})
+	SetLogRenderSQL(true)
+	defer func() {
+		SetLogger(&DumbLogger{})
+		SetLogRenderSQL(false)
+	}()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectExec(`UPDATE accounts SET phone=\? WHERE \(id=\?\)`).
+		WithArgs("120", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewCURD[Account, AccountParam]("accounts").Update(ctx,
+		&AccountParam{ID: P(int64(1))},
+		&AccountParam{Phone: P("120")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[DEBUG] [UpdateBuild] sql[UPDATE accounts SET phone='******' WHERE (id=1)]"
+	if out := buf.String(); !strings.Contains(out, want) {
+		t.Errorf("log output `%s`, want contains `%s`", out, want)
+	}
+}
+
+func TestSetRenderLocation(t *testing.T) {
+	defer SetRenderLocation(time.UTC)
+	SetRenderLocation(time.FixedZone("CST", 8*3600))
+
+	got, err := RenderSQL("SELECT ?", time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT '2022-01-02 11:04:05'"; got != want {
+		t.Errorf("RenderSQL() = `%v`, want `%v`", got, want)
+	}
+}
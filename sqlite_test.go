@@ -0,0 +1,687 @@
+//go:build cgo
+
+package sqlmy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newSQLiteCtx returns a context carrying an in-memory sqlite db with students table
+func newSQLiteCtx(t *testing.T, opts ...connOpt) context.Context {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every conn of :memory: is a different db
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE students (
+		id INTEGER PRIMARY KEY,
+		name VARCHAR(64) NOT NULL DEFAULT '',
+		status TINYINT NOT NULL DEFAULT 0
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := WithConn(context.Background(), func() (conn Conn, err error) { return db, nil }, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func sqliteStudents(t *testing.T, ctx context.Context) []Student {
+	t.Helper()
+
+	list, err := StudentCURD.QueryList(ctx, &StudentParam{OrderBy: P("id asc")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rst := make([]Student, 0, len(list))
+	for _, student := range list {
+		rst = append(rst, *student)
+	}
+	return rst
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+
+	// Insert
+	id, err := StudentCURD.Insert(ctx, &StudentParam{ID: P(int64(1)), Name: P("n1")})
+	if err != nil || id != 1 {
+		t.Fatalf("Insert() = %v, %v", id, err)
+	}
+
+	// Insert Ignore
+	id, err = StudentCURD.Insert(ctx, &StudentParam{ID: P(int64(1)), Name: P("n1-ignored")}, WithInsertType(InsertTypeIgnoreInsert))
+	if err != nil {
+		t.Fatalf("Insert() ignore err = %v", err)
+	}
+
+	// Insert List
+	id, err = StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(2)), Name: P("n2")},
+		{ID: P(int64(3)), Name: P("n3")},
+	})
+	if err != nil || id != 3 {
+		t.Fatalf("InsertList() = %v, %v", id, err)
+	}
+
+	// Insert List in batch
+	id, err = StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(4)), Name: P("n4")},
+		{ID: P(int64(5)), Name: P("n5")},
+		{ID: P(int64(6)), Name: P("n6")},
+	}, WithInsertBatchSize(2))
+	if err != nil || id != 6 {
+		t.Fatalf("InsertList() in batch = %v, %v", id, err)
+	}
+
+	// Query
+	student, err := StudentCURD.Query(ctx, nil)
+	if err != nil || student == nil || student.ID != 1 || student.Name != "n1" {
+		t.Fatalf("Query() = %v, %v", student, err)
+	}
+
+	// Query with where
+	student, err = StudentCURD.Query(ctx, &StudentParam{ID: P(int64(2))})
+	if err != nil || !reflect.DeepEqual(student, &Student{ID: 2, Name: "n2"}) {
+		t.Fatalf("Query() where = %v, %v", student, err)
+	}
+
+	// Query with fields
+	student, err = StudentCURD.Query(ctx, &StudentParam{ID: P(int64(2))}, WithSelectFileds("name"))
+	if err != nil || !reflect.DeepEqual(student, &Student{Name: "n2"}) {
+		t.Fatalf("Query() fields = %v, %v", student, err)
+	}
+
+	// Query empty
+	student, err = StudentCURD.Query(ctx, &StudentParam{ID: P(int64(100))})
+	if err != nil || student != nil {
+		t.Fatalf("Query() empty = %v, %v", student, err)
+	}
+
+	// Query List with in and limit
+	students, err := StudentCURD.QueryList(ctx, &StudentParam{
+		IDs:     []int64{2, 3, 4, 5},
+		OrderBy: P("id desc"),
+		Limit:   []uint{1, 2},
+	})
+	if err != nil || len(students) != 2 || students[0].ID != 4 || students[1].ID != 3 {
+		t.Fatalf("QueryList() = %v, %v", students, err)
+	}
+
+	// Update
+	affectedRows, err := StudentCURD.Update(ctx, &StudentParam{IDs: []int64{1, 2}}, &StudentParam{Status: P(1)})
+	if err != nil || affectedRows != 2 {
+		t.Fatalf("Update() = %v, %v", affectedRows, err)
+	}
+
+	// Delete
+	affectedRows, err = StudentCURD.Delete(ctx, &StudentParam{IDs: []int64{5, 6}})
+	if err != nil || affectedRows != 2 {
+		t.Fatalf("Delete() = %v, %v", affectedRows, err)
+	}
+
+	want := []Student{
+		{ID: 1, Name: "n1", Status: 1},
+		{ID: 2, Name: "n2", Status: 1},
+		{ID: 3, Name: "n3"},
+		{ID: 4, Name: "n4"},
+	}
+	if got := sqliteStudents(t, ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("students = %v, want %v", got, want)
+	}
+}
+
+func TestSQLiteReplace(t *testing.T) {
+	ctx := newSQLiteCtx(t)
+	curd := NewCURD[Student, StudentParam]("students", WithDialect(SQLite))
+
+	_, err := curd.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// INSERT OR REPLACE resets the columns not inserted
+	_, err = curd.Insert(ctx, &StudentParam{ID: P(int64(1)), Name: P("r1")}, WithInsertType(InsertTypeReplaceInsert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// upsert keeps the columns not inserted
+	_, err = curd.Insert(ctx, &StudentParam{ID: P(int64(2)), Name: P("u2")},
+		WithInsertType(InsertTypeReplaceInsert), WithConflictColumns("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Student{
+		{ID: 1, Name: "r1"},
+		{ID: 2, Name: "u2", Status: 2},
+	}
+	if got := sqliteStudents(t, ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("students = %v, want %v", got, want)
+	}
+}
+
+type sqliteOperatorParam struct {
+	NamePrefix *string `db:"name,prefix"`
+	NameLike   *string `db:"name,contains"`
+	Status     []int   `db:"status,between"`
+}
+
+func TestSQLiteOperators(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("a_1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("ab1"), Status: P(2)},
+		{ID: P(int64(3)), Name: P("b%!"), Status: P(3)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	curd := NewCURD[Student, sqliteOperatorParam]("students")
+	tests := []struct {
+		name  string
+		where *sqliteOperatorParam
+		want  []int64
+	}{
+		{name: "prefix escape _", where: &sqliteOperatorParam{NamePrefix: P("a_")}, want: []int64{1}},
+		{name: "contains escape", where: &sqliteOperatorParam{NameLike: P("%!")}, want: []int64{3}},
+		{name: "between", where: &sqliteOperatorParam{Status: []int{2, 3}}, want: []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := curd.QueryList(ctx, tt.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []int64{}
+			for _, student := range list {
+				got = append(got, student.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type sqliteExprParam struct {
+	ID     *int64 `db:"id"`
+	Name   *Expr  `db:"name"`
+	Status *Expr  `db:"status"`
+	Cond   *Expr  `db:"_expr"`
+}
+
+func TestSQLiteExpr(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	curd := NewCURD[Student, sqliteExprParam]("students")
+
+	_, err := curd.InsertList(ctx, []*sqliteExprParam{
+		{ID: P(int64(1)), Name: P(NewExpr("'n'||?", 1)), Status: P(NewExpr("1"))},
+		{ID: P(int64(2)), Name: P(NewExpr("'n'||?", 2)), Status: P(NewExpr("2"))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	affectedRows, err := curd.Update(ctx,
+		&sqliteExprParam{Cond: P(NewExpr("length(name)=? AND status>=?", 2, 2))},
+		&sqliteExprParam{Status: P(Incr(10))})
+	if err != nil || affectedRows != 1 {
+		t.Fatalf("Update() = %v, %v", affectedRows, err)
+	}
+
+	affectedRows, err = curd.Update(ctx,
+		&sqliteExprParam{ID: P(int64(1))},
+		&sqliteExprParam{Name: P(Now())})
+	if err != nil || affectedRows != 1 {
+		t.Fatalf("Update() now = %v, %v", affectedRows, err)
+	}
+
+	got := sqliteStudents(t, ctx)
+	if len(got) != 2 || got[0].Name == "n1" || got[1] != (Student{ID: 2, Name: "n2", Status: 12}) {
+		t.Errorf("students = %v", got)
+	}
+}
+
+type sqliteStatusCount struct {
+	Status int     `db:"status"`
+	Total  int64   `db:"total" agg:"count(*)"`
+	MaxID  float64 `db:"max_id" agg:"max(id)"`
+}
+
+func TestSQLiteAggregate(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(1)},
+		{ID: P(int64(3)), Name: P("n3"), Status: P(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := StudentCURD.Count(ctx, &StudentParam{Status: P(1)})
+	if err != nil || count != 2 {
+		t.Errorf("Count() = %v, %v", count, err)
+	}
+	sum, err := StudentCURD.Sum(ctx, "status", nil)
+	if err != nil || sum != 4 {
+		t.Errorf("Sum() = %v, %v", sum, err)
+	}
+	max, err := StudentCURD.Max(ctx, "id", &StudentParam{Status: P(1)})
+	if err != nil || max != 2 {
+		t.Errorf("Max() = %v, %v", max, err)
+	}
+	min, err := StudentCURD.Min(ctx, "id", &StudentParam{Status: P(100)})
+	if err != nil || min != 0 {
+		t.Errorf("Min() of nothing = %v, %v", min, err)
+	}
+	avg, err := StudentCURD.Avg(ctx, "id", nil)
+	if err != nil || avg != 2 {
+		t.Errorf("Avg() = %v, %v", avg, err)
+	}
+	if _, err := StudentCURD.Sum(ctx, "password", nil); err == nil {
+		t.Errorf("Sum() of unknown column should fail")
+	}
+
+	maxName, err := Aggregate[string](ctx, StudentCURD, AggMax, "name", nil)
+	if err != nil || maxName != "n3" {
+		t.Errorf("Aggregate() max name = %v, %v", maxName, err)
+	}
+	sumID, err := Aggregate[int64](ctx, StudentCURD, AggSum, "id", nil)
+	if err != nil || sumID != 6 {
+		t.Errorf("Aggregate() sum id = %v, %v", sumID, err)
+	}
+	noName, err := Aggregate[string](ctx, StudentCURD, AggMin, "name", &StudentParam{Status: P(100)})
+	if err != nil || noName != "" {
+		t.Errorf("Aggregate() min name of nothing = %v, %v", noName, err)
+	}
+	if _, err := Aggregate[int64](ctx, StudentCURD, AggFunc("MEDIAN"), "id", nil); err == nil {
+		t.Errorf("Aggregate() of unsupported function should fail")
+	}
+
+	groups, err := QueryGroup[sqliteStatusCount](ctx, StudentCURD, []string{"status"}, &StudentParam{OrderBy: P("status asc")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*sqliteStatusCount{{Status: 1, Total: 2, MaxID: 2}, {Status: 2, Total: 1, MaxID: 3}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("QueryGroup() = %v, want %v", groups, want)
+	}
+}
+
+type sqliteOrder struct {
+	ID        int64 `db:"id"`
+	StudentID int64 `db:"student_id"`
+	Amount    int   `db:"amount"`
+}
+
+type sqliteOrderParam struct {
+	ID        *int64  `db:"id"`
+	StudentID *int64  `db:"student_id"`
+	Amount    *int    `db:"amount,>="`
+	Expr      *Expr   `db:"_expr"`
+	OrderBy   OrderBy `db:"_orderby"`
+}
+
+type sqliteOrderStudent struct {
+	Order   sqliteOrder `db:"orders.,inline"`
+	Student *Student    `db:"students.,inline"`
+}
+
+type sqliteOrderStudentFlat struct {
+	OrderID int64  `db:"orders.id"`
+	Name    string `db:"students.name"`
+}
+
+func TestSQLiteJoin(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	if _, err := ExecContext(ctx, `CREATE TABLE orders (id INTEGER PRIMARY KEY, student_id INTEGER, amount INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	orderCURD := NewCURD[sqliteOrder, sqliteOrderParam]("orders")
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = orderCURD.InsertList(ctx, []*sqliteOrderParam{
+		{ID: P(int64(1)), StudentID: P(int64(1)), Amount: P(10)},
+		{ID: P(int64(2)), StudentID: P(int64(2)), Amount: P(20)},
+		{ID: P(int64(3)), StudentID: P(int64(1)), Amount: P(30)},
+		{ID: P(int64(4)), StudentID: P(int64(3)), Amount: P(40)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	join := NewJoin(orderCURD.Where(&sqliteOrderParam{Amount: P(20), OrderBy: OrderBy{Desc("orders.id")}})).
+		LeftJoin(StudentCURD.Where(nil), On("student_id", "id"))
+	got, err := QueryJoin[sqliteOrderStudent](ctx, join)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*sqliteOrderStudent{
+		{Order: sqliteOrder{ID: 4, StudentID: 3, Amount: 40}, Student: &Student{}},
+		{Order: sqliteOrder{ID: 3, StudentID: 1, Amount: 30}, Student: &Student{ID: 1, Name: "n1", Status: 1}},
+		{Order: sqliteOrder{ID: 2, StudentID: 2, Amount: 20}, Student: &Student{ID: 2, Name: "n2", Status: 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryJoin() = %v, want %v", got, want)
+	}
+
+	// the where of left joined table keeps the orders whose student does not match
+	join = NewJoin(orderCURD.Where(&sqliteOrderParam{Amount: P(20), OrderBy: OrderBy{Desc("orders.id")}})).
+		LeftJoin(StudentCURD.Where(&StudentParam{Status: P(1)}), On("student_id", "id"))
+	got, err = QueryJoin[sqliteOrderStudent](ctx, join)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []*sqliteOrderStudent{
+		{Order: sqliteOrder{ID: 4, StudentID: 3, Amount: 40}, Student: &Student{}},
+		{Order: sqliteOrder{ID: 3, StudentID: 1, Amount: 30}, Student: &Student{ID: 1, Name: "n1", Status: 1}},
+		{Order: sqliteOrder{ID: 2, StudentID: 2, Amount: 20}, Student: &Student{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryJoin() left join where = %v, want %v", got, want)
+	}
+
+	join = NewJoin(orderCURD.Where(&sqliteOrderParam{OrderBy: OrderBy{Asc("orders.id")}})).
+		InnerJoin(StudentCURD.Where(&StudentParam{Status: P(1)}), On("student_id", "id"))
+	flat, err := QueryJoin[sqliteOrderStudentFlat](ctx, join)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFlat := []*sqliteOrderStudentFlat{{OrderID: 1, Name: "n1"}, {OrderID: 3, Name: "n1"}}
+	if !reflect.DeepEqual(flat, wantFlat) {
+		t.Errorf("QueryJoin() flat = %v, want %v", flat, wantFlat)
+	}
+}
+
+type sqliteSubQueryParam struct {
+	IDs       *SubQuery `db:"id,in"`
+	NotIDs    *SubQuery `db:"id,not in"`
+	HasOrders *SubQuery `db:"orders,exists"`
+	OrderBy   *string   `db:"_orderby"`
+}
+
+func TestSQLiteSubQuery(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	if _, err := ExecContext(ctx, `CREATE TABLE orders (id INTEGER PRIMARY KEY, student_id INTEGER, amount INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	orderCURD := NewCURD[sqliteOrder, sqliteOrderParam]("orders")
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(2)},
+		{ID: P(int64(3)), Name: P("n3"), Status: P(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = orderCURD.InsertList(ctx, []*sqliteOrderParam{
+		{ID: P(int64(1)), StudentID: P(int64(1)), Amount: P(10)},
+		{ID: P(int64(2)), StudentID: P(int64(2)), Amount: P(20)},
+		{ID: P(int64(3)), StudentID: P(int64(1)), Amount: P(30)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subQueryCURD := NewCURD[Student, sqliteSubQueryParam]("students")
+	tests := []struct {
+		name  string
+		where *sqliteSubQueryParam
+		want  []int64
+	}{
+		{
+			name:  "in",
+			where: &sqliteSubQueryParam{IDs: orderCURD.SubQuery("student_id", &sqliteOrderParam{Amount: P(20)})},
+			want:  []int64{1, 2},
+		},
+		{
+			name:  "not in",
+			where: &sqliteSubQueryParam{NotIDs: orderCURD.SubQuery("student_id", nil)},
+			want:  []int64{3},
+		},
+		{
+			name: "exists",
+			where: &sqliteSubQueryParam{HasOrders: orderCURD.SubQuery("", &sqliteOrderParam{
+				Amount: P(30),
+				Expr:   P(NewExpr("orders.student_id=students.id")),
+			})},
+			want: []int64{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.where.OrderBy = P("id asc")
+			students, err := subQueryCURD.QueryList(ctx, tt.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, student := range students {
+				got = append(got, student.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryList() ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err = subQueryCURD.QueryList(ctx, &sqliteSubQueryParam{IDs: orderCURD.SubQuery("name", nil)})
+	if !errors.Is(err, ErrColumnNotAllowed) {
+		t.Errorf("QueryList() err = %v, want %v", err, ErrColumnNotAllowed)
+	}
+}
+
+func TestSQLiteCompound(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(2)},
+		{ID: P(int64(3)), Name: P("n3"), Status: P(1)},
+		{ID: P(int64(4)), Name: P("n4"), Status: P(3)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := StudentCURD.Select(&StudentParam{Status: P(1)}).
+		UnionAll(StudentCURD.Select(&StudentParam{OrderBy: P("id desc"), Limit: []uint{0, 1}})).
+		QueryList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Student{{ID: 1, Name: "n1", Status: 1}, {ID: 3, Name: "n3", Status: 1}, {ID: 4, Name: "n4", Status: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryList() union = %v, want %v", got, want)
+	}
+
+	activeCURD := NewCURD[Student, StudentParam]("active")
+	got, err = activeCURD.Select(&StudentParam{IDs: []int64{2, 3, 4}, OrderBy: P("id desc")}).
+		With("active", StudentCURD.Select(&StudentParam{Status: P(1)}).Union(StudentCURD.Select(&StudentParam{Status: P(2)}))).
+		QueryList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []*Student{{ID: 3, Name: "n3", Status: 1}, {ID: 2, Name: "n2", Status: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryList() with = %v, want %v", got, want)
+	}
+}
+
+type sqliteRankedStudent struct {
+	Student
+	Rank int `db:"rn"`
+}
+
+func TestSQLiteSelectExpr(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(2)},
+		{ID: P(int64(3)), Name: P("n3"), Status: P(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rankedCURD := NewCURD[sqliteRankedStudent, StudentParam]("students")
+	got, err := rankedCURD.QueryList(ctx, &StudentParam{OrderBy: P("id asc")},
+		WithSelectExprs(As("ROW_NUMBER() OVER (PARTITION BY status ORDER BY id DESC)", "rn")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*sqliteRankedStudent{
+		{Student: Student{ID: 1, Name: "n1", Status: 1}, Rank: 2},
+		{Student: Student{ID: 2, Name: "n2", Status: 2}, Rank: 1},
+		{Student: Student{ID: 3, Name: "n3", Status: 1}, Rank: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryList() = %v, want %v", got, want)
+	}
+
+	got, err = rankedCURD.QueryList(ctx, &StudentParam{OrderBy: P("id asc")},
+		WithSelectFileds("id"), WithSelectExprs(As("COUNT(*) OVER ()", "rn")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []*sqliteRankedStudent{
+		{Student: Student{ID: 1}, Rank: 3},
+		{Student: Student{ID: 2}, Rank: 3},
+		{Student: Student{ID: 3}, Rank: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryList() = %v, want %v", got, want)
+	}
+
+	_, err = rankedCURD.QueryList(ctx, nil, WithSelectFileds("id"), WithSelectExprs(As("COUNT(*)", "cnt")))
+	if !errors.Is(err, ErrColumnNotAllowed) {
+		t.Errorf("QueryList() err = %v, want %v", err, ErrColumnNotAllowed)
+	}
+}
+
+func TestSQLiteUpdateList(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	_, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1"), Status: P(1)},
+		{ID: P(int64(2)), Name: P("n2"), Status: P(1)},
+		{ID: P(int64(3)), Name: P("n3"), Status: P(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	affectedRows, err := StudentCURD.UpdateList(ctx, "id", []*StudentParam{
+		{ID: P(int64(1)), Name: P("m1")},
+		{ID: P(int64(3)), Name: P("m3"), Status: P(3)},
+		{ID: P(int64(4)), Name: P("m4")},
+	}, WithUpdateBatchSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affectedRows != 2 {
+		t.Errorf("UpdateList() affectedRows = %d, want 2", affectedRows)
+	}
+
+	want := []Student{{ID: 1, Name: "m1", Status: 1}, {ID: 2, Name: "n2", Status: 1}, {ID: 3, Name: "m3", Status: 3}}
+	if got := sqliteStudents(t, ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("students = %v, want %v", got, want)
+	}
+}
+
+func TestSQLiteInsertBatches(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+
+	datas := make([]*StudentParam, 0, 10)
+	for i := 1; i <= 10; i++ {
+		datas = append(datas, &StudentParam{ID: P(int64(i)), Name: P(fmt.Sprintf("n%d", i))})
+	}
+	// a row takes 2 placeholders and about 30 bytes
+	results, err := StudentCURD.InsertBatches(ctx, datas, WithInsertBatchSize(4), WithMaxPacketSize(70))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []InsertBatchResult{
+		{Rows: 2, RowsAffected: 2, FirstInsertedID: 1, LastInsertedID: 2},
+		{Rows: 2, RowsAffected: 2, FirstInsertedID: 3, LastInsertedID: 4},
+		{Rows: 2, RowsAffected: 2, FirstInsertedID: 5, LastInsertedID: 6},
+		{Rows: 2, RowsAffected: 2, FirstInsertedID: 7, LastInsertedID: 8},
+		{Rows: 2, RowsAffected: 2, FirstInsertedID: 9, LastInsertedID: 10},
+	}
+	for i := range results {
+		results[i].insertID = 0
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("InsertBatches() = %+v, want %+v", results, want)
+	}
+	if got := sqliteStudents(t, ctx); len(got) != 10 {
+		t.Errorf("len(students) = %d, want 10", len(got))
+	}
+
+	// the rows are divisible by the batch size
+	id, err := StudentCURD.InsertList(ctx, []*StudentParam{
+		{ID: P(int64(11)), Name: P("n11")},
+		{ID: P(int64(12)), Name: P("n12")},
+	}, WithInsertBatchSize(1))
+	if err != nil || id != 12 {
+		t.Errorf("InsertList() = %d, %v, want 12, <nil>", id, err)
+	}
+}
+
+func TestSQLiteAtomic(t *testing.T) {
+	ctx := newSQLiteCtx(t, ConnDialect(SQLite))
+	datas := []*StudentParam{
+		{ID: P(int64(1)), Name: P("n1")},
+		{ID: P(int64(2)), Name: P("n2")},
+		{ID: P(int64(1)), Name: P("dup")},
+	}
+
+	if _, err := StudentCURD.InsertList(ctx, datas, WithInsertBatchSize(2), WithAtomic()); err == nil {
+		t.Fatal("InsertList() err = nil, want error")
+	}
+	if got := sqliteStudents(t, ctx); len(got) != 0 {
+		t.Errorf("students = %v, want empty after rollback", got)
+	}
+
+	affectedRows, err := StudentCURD.UpdateList(ctx, "id", datas, WithUpdateBatchSize(1), WithContinueOnError())
+	if err != nil || affectedRows != 0 {
+		t.Errorf("UpdateList() = %d, %v, want 0, <nil>", affectedRows, err)
+	}
+
+	_, err = StudentCURD.InsertList(ctx, datas, WithInsertBatchSize(2), WithContinueOnError())
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Batches) != 1 || batchErr.Batches[0] != 1 {
+		t.Errorf("InsertList() err = %v, want BatchError of batch 1", err)
+	}
+	if got := sqliteStudents(t, ctx); len(got) != 2 {
+		t.Errorf("students = %v, want 2 rows", got)
+	}
+}
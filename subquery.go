@@ -0,0 +1,23 @@
+package sqlmy
+
+import (
+	"reflect"
+
+	"github.com/liuximu/sqlmy/internal"
+)
+
+// SubQuery is the value of Param's field whose operator is `in`, `not in`, `exists` or `not exists`,
+// it is created by CURD.SubQuery. For `exists` and `not exists`, the column of tag is only a label,
+// like `db:"orders,exists"`, the correlation can be set by the `_expr` of subquery's where.
+type SubQuery = internal.SubQuery
+
+// SubQuery returns the SubQuery selecting column from curd's table, column can be empty for exists,
+// the column and the ones of where's `_orderby` and `_groupby` must be the columns of Data
+func (curd *CURD[Data, Param]) SubQuery(column string, where *Param) *SubQuery {
+	return &SubQuery{
+		Table:   curd.table,
+		Column:  column,
+		Where:   where,
+		Columns: internal.Columns(reflect.TypeOf((*Data)(nil))),
+	}
+}
@@ -62,9 +62,11 @@ func closeTx(ctx context.Context, succ bool) error {
 		return nil
 	}
 
+	tx := hc.tx
+	hc.tx = nil
 	if succ {
-		return hc.tx.Commit()
+		return tx.Commit()
 	}
 
-	return hc.tx.Rollback()
+	return tx.Rollback()
 }